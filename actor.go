@@ -12,3 +12,11 @@ type Actor struct {
 	SeriesID    int    `json:"seriesId"`
 	SortOrder   int    `json:"sortOrder"`
 }
+
+// ImageURL returns the complete URL of the actor image.
+func (a *Actor) ImageURL() string {
+	if a.Image == "" {
+		return ""
+	}
+	return ImageURL(a.Image)
+}
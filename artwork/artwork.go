@@ -0,0 +1,271 @@
+// Package artwork downloads the images of the TVDB series, like posters,
+// fanart and actor headshots, to the local filesystem.
+//
+// Images are fetched concurrently by a bounded pool of workers, written to a
+// temporary .part file that is renamed in place only when the download is
+// complete and verified, so a partially written image is never left at the
+// destination path. Interrupted downloads are resumed from the .part file,
+// only if the remote image is unchanged according to the ETag or Last-Modified
+// value stored next to it, and images already present with the same size of
+// the remote ones are skipped.
+package artwork
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // register decoders used to verify the images
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pioz/tvdb"
+)
+
+// Item is an image to download.
+type Item struct {
+	URL string
+	// Destination path of the image.
+	Path string
+	// Expected width and height of the image, zero if unknown.
+	Width  int
+	Height int
+}
+
+// ErrInvalidPath is returned when the TVDB path of an image is absolute or
+// leaves the destination directory.
+var ErrInvalidPath = errors.New("invalid image path")
+
+// ImageItem returns the item to download the image in dir. The image is
+// saved with its TVDB relative path, like dir/posters/121361-1.jpg.
+func ImageItem(img tvdb.Image, dir string) (Item, error) {
+	path, err := localPath(dir, img.FileName)
+	if err != nil {
+		return Item{}, err
+	}
+	item := Item{URL: img.URL(), Path: path}
+	item.Width, item.Height, _ = img.Size()
+	return item, nil
+}
+
+// ActorItem returns the item to download the actor image in dir.
+func ActorItem(a tvdb.Actor, dir string) (Item, error) {
+	path, err := localPath(dir, a.Image)
+	if err != nil {
+		return Item{}, err
+	}
+	return Item{URL: a.ImageURL(), Path: path}, nil
+}
+
+// localPath joins dir and the slash separated TVDB path name, returning
+// ErrInvalidPath if the result is not inside dir.
+func localPath(dir, name string) (string, error) {
+	name = filepath.FromSlash(name)
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPath, name)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// Result is the outcome of the download of an item.
+type Result struct {
+	Item Item
+	// Skipped is true if the image was already present.
+	Skipped bool
+	// Number of bytes downloaded.
+	Bytes int64
+	Err   error
+}
+
+// ErrDimensions is returned when the downloaded image has not the expected
+// width and height.
+var ErrDimensions = errors.New("image dimensions don't match")
+
+// Downloader downloads images.
+type Downloader struct {
+	// HTTP client used for the requests. If nil http.DefaultClient is used.
+	Client *http.Client
+	// Number of concurrent downloads (default 4).
+	Workers int
+}
+
+// Download downloads the items and returns a result for each of them, in the
+// same order. Downloads not started when ctx is done get the context error.
+func (d *Downloader) Download(ctx context.Context, items []Item) []Result {
+	workers := d.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+	results := make([]Result, len(items))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := range items {
+		results[i].Item = items[i]
+		if ctx.Err() != nil {
+			results[i].Err = ctx.Err()
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(r *Result) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r.Skipped, r.Bytes, r.Err = d.download(ctx, r.Item)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results
+}
+
+func (d *Downloader) client() *http.Client {
+	if d.Client != nil {
+		return d.Client
+	}
+	return http.DefaultClient
+}
+
+func (d *Downloader) download(ctx context.Context, item Item) (bool, int64, error) {
+	if item.URL == "" {
+		return false, 0, errors.New("missing image url")
+	}
+	if info, err := os.Stat(item.Path); err == nil {
+		if size, err := d.remoteSize(ctx, item.URL); err == nil && size == info.Size() {
+			return true, 0, nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(item.Path), 0755); err != nil {
+		return false, 0, err
+	}
+
+	part := item.Path + ".part"
+	meta := part + ".meta"
+	req, err := http.NewRequestWithContext(ctx, "GET", item.URL, nil)
+	if err != nil {
+		return false, 0, err
+	}
+	// Resume only if the part file can be validated with If-Range, otherwise
+	// the server could append the tail of a different image.
+	if info, err := os.Stat(part); err == nil && info.Size() > 0 {
+		if validator, err := os.ReadFile(meta); err == nil && len(validator) > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", info.Size()))
+			req.Header.Set("If-Range", string(validator))
+		}
+	}
+	resp, err := d.client().Do(req)
+	if err != nil {
+		return false, 0, err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusOK:
+		flags |= os.O_TRUNC
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The part file is already complete.
+		flags |= os.O_APPEND
+	default:
+		return false, 0, &tvdb.RequestError{Code: resp.StatusCode}
+	}
+	if ct := resp.Header.Get("Content-Type"); resp.StatusCode != http.StatusRequestedRangeNotSatisfiable && !strings.HasPrefix(ct, "image/") {
+		return false, 0, fmt.Errorf("unexpected content type %q", ct)
+	}
+	if resp.StatusCode == http.StatusOK {
+		if err := writeValidator(meta, resp.Header); err != nil {
+			return false, 0, err
+		}
+	}
+	f, err := os.OpenFile(part, flags, 0644)
+	if err != nil {
+		return false, 0, err
+	}
+	var n int64
+	if resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		n, err = io.Copy(f, resp.Body)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return false, n, err
+	}
+	if err := verify(part, item); err != nil {
+		os.Remove(part)
+		os.Remove(meta)
+		return false, n, err
+	}
+	if err := os.Rename(part, item.Path); err != nil {
+		return false, n, err
+	}
+	os.Remove(meta)
+	return false, n, nil
+}
+
+// writeValidator stores in path the ETag, or the Last-Modified value, of the
+// response used to resume the download. A response without them can't be
+// resumed and path is removed.
+func writeValidator(path string, header http.Header) error {
+	validator := header.Get("ETag")
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		// If-Range requires a strong validator.
+		validator = header.Get("Last-Modified")
+	}
+	if validator == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(path, []byte(validator), 0644)
+}
+
+// remoteSize returns the size of the remote image using a HEAD request.
+func (d *Downloader) remoteSize(ctx context.Context, url string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := d.client().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, &tvdb.RequestError{Code: resp.StatusCode}
+	}
+	if resp.ContentLength < 0 {
+		return 0, errors.New("unknown content length")
+	}
+	return resp.ContentLength, nil
+}
+
+// verify decodes the header of the image and checks its dimensions. A file
+// that can't be decoded as a JPEG, PNG or GIF image is not valid.
+func verify(path string, item Item) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return fmt.Errorf("invalid image: %w", err)
+	}
+	if (item.Width > 0 && cfg.Width != item.Width) || (item.Height > 0 && cfg.Height != item.Height) {
+		return fmt.Errorf("%w: expected %dx%d, got %dx%d", ErrDimensions, item.Width, item.Height, cfg.Width, cfg.Height)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package artwork_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pioz/tvdb"
+	"github.com/pioz/tvdb/artwork"
+	"github.com/stretchr/testify/assert"
+)
+
+func pngImage(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDownload(t *testing.T) {
+	data := pngImage(t, 20, 10)
+	var gets atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			gets.Add(1)
+		}
+		switch r.URL.Path {
+		case "/text":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>"))
+			return
+		case "/untyped":
+			// Disable the content type sniffing.
+			w.Header()["Content-Type"] = nil
+			w.Write(data)
+			return
+		case "/corrupt":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("not an image"))
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "image.png", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	items := []artwork.Item{
+		{URL: server.URL + "/ok", Path: filepath.Join(dir, "posters", "ok.png"), Width: 20, Height: 10},
+		{URL: server.URL + "/wrong", Path: filepath.Join(dir, "wrong.png"), Width: 30, Height: 10},
+		{URL: server.URL + "/text", Path: filepath.Join(dir, "text.png")},
+		{URL: server.URL + "/untyped", Path: filepath.Join(dir, "untyped.png")},
+		{URL: server.URL + "/corrupt", Path: filepath.Join(dir, "corrupt.png")},
+	}
+	// Simulate an interrupted download.
+	partial := filepath.Join(dir, "resumed.png")
+	os.WriteFile(partial+".part", data[:10], 0644)
+	os.WriteFile(partial+".part.meta", []byte(`"v1"`), 0644)
+	// The image changed after the interruption.
+	changed := filepath.Join(dir, "changed.png")
+	os.WriteFile(changed+".part", []byte("old image"), 0644)
+	os.WriteFile(changed+".part.meta", []byte(`"v0"`), 0644)
+	// The interrupted download can't be validated.
+	unvalidated := filepath.Join(dir, "unvalidated.png")
+	os.WriteFile(unvalidated+".part", []byte("old image"), 0644)
+	items = append(items,
+		artwork.Item{URL: server.URL + "/resumed", Path: partial},
+		artwork.Item{URL: server.URL + "/changed", Path: changed},
+		artwork.Item{URL: server.URL + "/unvalidated", Path: unvalidated},
+	)
+
+	d := artwork.Downloader{Workers: 2}
+	results := d.Download(context.Background(), items)
+	assert.Nil(t, results[0].Err)
+	assert.ErrorIs(t, results[1].Err, artwork.ErrDimensions)
+	assert.NotNil(t, results[2].Err)
+	assert.NotNil(t, results[3].Err)
+	assert.NotNil(t, results[4].Err)
+	assert.NoFileExists(t, filepath.Join(dir, "corrupt.png"))
+	assert.Nil(t, results[5].Err)
+	assert.Equal(t, int64(len(data)-10), results[5].Bytes)
+	got, _ := os.ReadFile(partial)
+	assert.Equal(t, data, got)
+	assert.NoFileExists(t, partial+".part.meta")
+	for i, path := range []string{changed, unvalidated} {
+		// Downloaded again from the start.
+		assert.Nil(t, results[6+i].Err)
+		assert.Equal(t, int64(len(data)), results[6+i].Bytes)
+		got, _ := os.ReadFile(path)
+		assert.Equal(t, data, got)
+	}
+	assert.NoFileExists(t, filepath.Join(dir, "wrong.png"))
+	assert.NoFileExists(t, filepath.Join(dir, "wrong.png.part"))
+
+	gets.Store(0)
+	results = d.Download(context.Background(), items[:1])
+	assert.True(t, results[0].Skipped)
+	assert.Equal(t, int32(0), gets.Load())
+}
+
+func TestImageItem(t *testing.T) {
+	dir := t.TempDir()
+	item, err := artwork.ImageItem(tvdb.Image{FileName: "posters/121361-1.jpg", Resolution: "680x1000"}, dir)
+	if assert.NoError(t, err) {
+		assert.Equal(t, filepath.Join(dir, "posters", "121361-1.jpg"), item.Path)
+		assert.Equal(t, 680, item.Width)
+	}
+	_, err = artwork.ImageItem(tvdb.Image{FileName: "../../etc/passwd"}, dir)
+	assert.ErrorIs(t, err, artwork.ErrInvalidPath)
+	_, err = artwork.ActorItem(tvdb.Actor{Image: "/actors/1.jpg"}, dir)
+	assert.ErrorIs(t, err, artwork.ErrInvalidPath)
+	_, err = artwork.ActorItem(tvdb.Actor{Image: "actors/../../1.jpg"}, dir)
+	assert.ErrorIs(t, err, artwork.ErrInvalidPath)
+}
+
+func TestDownloadCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	dir := t.TempDir()
+	items := make([]artwork.Item, 3)
+	for i := range items {
+		items[i] = artwork.Item{URL: server.URL, Path: filepath.Join(dir, strconv.Itoa(i)+".png")}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	d := artwork.Downloader{Workers: 1}
+	// The items waiting for a worker must not block after the cancellation.
+	results := d.Download(ctx, items)
+	for _, r := range results {
+		assert.ErrorIs(t, r.Err, context.Canceled)
+	}
+}
@@ -0,0 +1,147 @@
+package tvdb
+
+import (
+	"context"
+	"errors"
+)
+
+// batchWorkers is the default number of series retrieved concurrently by
+// GetSeriesBatch.
+const batchWorkers = 8
+
+// imageKeyTypes are the types of the series images.
+var imageKeyTypes = []string{"fanart", "poster", "season", "seasonwide", "series"}
+
+// fullWorkers is the number of concurrent requests performed by
+// GetSeriesFull.
+const fullWorkers = 8
+
+// Include selects the resources retrieved with a series by GetSeriesFull and
+// GetSeriesBatch.
+type Include struct {
+	Episodes bool
+	Actors   bool
+	Summary  bool
+	// Images of all the types.
+	Images bool
+	// If true every episode is retrieved with GetEpisode, to fill the fields
+	// that the episodes list omits, like the directors and the writers. It
+	// implies Episodes and performs a request for each episode.
+	EpisodeDetails bool
+}
+
+// BatchOptions holds the options of GetSeriesBatch.
+type BatchOptions struct {
+	// Number of series retrieved concurrently (default 8).
+	Workers int
+	// Resources retrieved with every series.
+	Include
+}
+
+// BatchResult is the result of a series retrieved by GetSeriesBatch.
+type BatchResult struct {
+	ID     int
+	Series Series
+	// Error retrieving the series or one of its requested resources. Episodes,
+	// actors, summary and images not found are left empty and are not an
+	// error.
+	Err error
+}
+
+// GetSeriesBatch retrieves concurrently the series identified by ids and
+// returns a result for every id, in the same order. A series that can't be
+// retrieved doesn't fail the batch: its error is returned in its result. At
+// most opts.Workers requests are performed at the same time and the requests
+// wait for the client RateLimiter, so the load on the api is bounded by both.
+// If ctx is done the series not yet retrieved, or waiting for the rate
+// limiter, get the context error.
+func (c *Client) GetSeriesBatch(ctx context.Context, ids []int, opts BatchOptions) []BatchResult {
+	results := make([]BatchResult, len(ids))
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = batchWorkers
+	}
+	errs := runWorkers(ctx, len(ids), workers, func(i int) error {
+		// Every worker retrieves the resources of its series sequentially.
+		s, err := c.getSeriesFull(ctx, ids[i], opts.Include, 1)
+		results[i].Series = s
+		return err
+	})
+	for i, id := range ids {
+		results[i].ID = id
+		results[i].Err = errs[i]
+	}
+	return results
+}
+
+// GetSeriesFull retrieves the series identified by id with the resources
+// selected by inc, performing the requests concurrently. Episodes, actors,
+// summary and images not found are left empty. If a request fails the
+// errors are joined in the returned error.
+func (c *Client) GetSeriesFull(ctx context.Context, id int, inc Include) (Series, error) {
+	return c.getSeriesFull(ctx, id, inc, fullWorkers)
+}
+
+func (c *Client) getSeriesFull(ctx context.Context, id int, inc Include, workers int) (Series, error) {
+	c = c.withContext(ctx)
+	s := Series{ID: id}
+	var (
+		episodes Series
+		actors   Series
+		summary  Series
+		images   = make([][]Image, len(imageKeyTypes))
+	)
+	calls := []func() error{
+		func() error { return c.GetSeries(&s) },
+	}
+	if inc.Episodes || inc.EpisodeDetails {
+		episodes.ID = id
+		calls = append(calls, func() error { return ignoreNotFound(c.GetSeriesEpisodes(&episodes, nil)) })
+	}
+	if inc.Actors {
+		actors.ID = id
+		calls = append(calls, func() error { return ignoreNotFound(c.GetSeriesActors(&actors)) })
+	}
+	if inc.Summary {
+		summary.ID = id
+		calls = append(calls, func() error { return ignoreNotFound(c.GetSeriesSummary(&summary)) })
+	}
+	if inc.Images {
+		for i, keyType := range imageKeyTypes {
+			i, keyType := i, keyType
+			calls = append(calls, func() error {
+				var err error
+				images[i], err = c.seriesImages(id, keyType)
+				return ignoreNotFound(err)
+			})
+		}
+	}
+	if err := errors.Join(runWorkers(ctx, len(calls), workers, func(i int) error { return calls[i]() })...); err != nil {
+		return s, err
+	}
+	s.Episodes = episodes.Episodes
+	s.Actors = actors.Actors
+	s.Summary = summary.Summary
+	for _, imgs := range images {
+		s.Images = append(s.Images, imgs...)
+	}
+
+	if inc.EpisodeDetails {
+		errs := runWorkers(ctx, len(s.Episodes), workers, func(i int) error {
+			// An episode removed after the list was retrieved is left as is.
+			return ignoreNotFound(c.GetEpisode(&s.Episodes[i]))
+		})
+		if err := errors.Join(errs...); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// ignoreNotFound returns nil if err is a 404 response error.
+func ignoreNotFound(err error) error {
+	if HaveCodeError(404, err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,49 @@
+package tvdb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSeriesFullEpisodeNotFound(t *testing.T) {
+	responses := map[string]string{
+		"/series/1":                `{"data":{"id":1,"seriesName":"One"}}`,
+		"/series/1/episodes/query": `{"data":[{"id":11,"airedSeason":1,"airedEpisodeNumber":1},{"id":12,"airedSeason":1,"airedEpisodeNumber":2}]}`,
+		"/episodes/11":             `{"data":{"id":11,"airedSeason":1,"airedEpisodeNumber":1,"directors":["Director"]}}`,
+	}
+	c := Client{Language: "en", client: http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, ok := responses[req.URL.Path]
+		if !ok {
+			return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader(`{"Error":"ID: 12 not found"}`))}, nil
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}}
+	s, err := c.GetSeriesFull(context.Background(), 1, Include{EpisodeDetails: true})
+	if assert.NoError(t, err) && assert.Len(t, s.Episodes, 2) {
+		assert.Equal(t, []string{"Director"}, s.Episodes[0].Directors)
+		assert.Equal(t, 12, s.Episodes[1].ID)
+	}
+}
+
+func TestGetSeriesBatchRateLimiter(t *testing.T) {
+	c := Client{Language: "en", RateLimiter: NewRateLimiter(0.001, 1), client: http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(`{"data":{"id":1,"seriesName":"One"}}`))}, nil
+	})}}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	results := c.GetSeriesBatch(ctx, []int{1, 2}, BatchOptions{Workers: 2})
+	var failed int
+	for _, r := range results {
+		if r.Err != nil {
+			assert.ErrorIs(t, r.Err, context.DeadlineExceeded)
+			failed++
+		}
+	}
+	assert.Equal(t, 1, failed, "only one request is allowed")
+}
@@ -0,0 +1,81 @@
+package tvdb_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/pioz/tvdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSeriesBatch(t *testing.T) {
+	cache := tvdb.NewDiskCache(t.TempDir(), true)
+	seeds := map[string]string{
+		tvdb.CacheKey("/series/1", nil, "en"):                                      `{"data":{"id":1,"seriesName":"One"}}`,
+		tvdb.CacheKey("/series/2", nil, "en"):                                      `{"data":{"id":2,"seriesName":"Two"}}`,
+		tvdb.CacheKey("/series/1/actors", nil, "en"):                               `{"data":[{"id":10,"name":"Actor"}]}`,
+		tvdb.CacheKey("/series/2/actors", nil, "en"):                               `{"data":[]}`,
+		tvdb.CacheKey("/series/1/episodes/query", url.Values{"page": {"1"}}, "en"): `{"data":[{"id":11}]}`,
+		tvdb.CacheKey("/series/2/episodes/query", url.Values{"page": {"1"}}, "en"): `{"data":[]}`,
+	}
+	for key, data := range seeds {
+		if err := cache.Seed(key, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := tvdb.Client{Language: "en", Cache: cache}
+	results := c.GetSeriesBatch(context.Background(), []int{1, 3, 2}, tvdb.BatchOptions{Workers: 2, Include: tvdb.Include{Episodes: true, Actors: true}})
+	if assert.Len(t, results, 3) {
+		assert.Equal(t, 1, results[0].ID)
+		assert.Nil(t, results[0].Err)
+		assert.Equal(t, "One", results[0].Series.SeriesName)
+		assert.Len(t, results[0].Series.Episodes, 1)
+		assert.Len(t, results[0].Series.Actors, 1)
+		assert.Equal(t, 3, results[1].ID)
+		assert.ErrorIs(t, results[1].Err, tvdb.ErrCacheMiss, "a failed series doesn't fail the batch")
+		assert.Equal(t, "Two", results[2].Series.SeriesName)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results = c.GetSeriesBatch(ctx, []int{1}, tvdb.BatchOptions{})
+	assert.Equal(t, context.Canceled, results[0].Err)
+}
+
+func TestGetSeriesFull(t *testing.T) {
+	cache := tvdb.NewDiskCache(t.TempDir(), true)
+	seeds := map[string]string{
+		tvdb.CacheKey("/series/1", nil, "en"):                                      `{"data":{"id":1,"seriesName":"One"}}`,
+		tvdb.CacheKey("/series/1/actors", nil, "en"):                               `{"data":[{"id":10,"name":"Actor"}]}`,
+		tvdb.CacheKey("/series/1/episodes/summary", nil, "en"):                     `{"data":{"airedSeasons":["1"],"airedEpisodes":"2"}}`,
+		tvdb.CacheKey("/series/1/episodes/query", url.Values{"page": {"1"}}, "en"): `{"data":[{"id":11,"airedSeason":1,"airedEpisodeNumber":1},{"id":12,"airedSeason":1,"airedEpisodeNumber":2}]}`,
+		tvdb.CacheKey("/episodes/11", nil, "en"):                                   `{"data":{"id":11,"airedSeason":1,"airedEpisodeNumber":1,"directors":["Director"]}}`,
+		tvdb.CacheKey("/episodes/12", nil, "en"):                                   `{"data":{"id":12,"airedSeason":1,"airedEpisodeNumber":2,"writers":["Writer"]}}`,
+	}
+	for i, keyType := range []string{"fanart", "poster", "season", "seasonwide", "series"} {
+		seeds[tvdb.CacheKey("/series/1/images/query", url.Values{"keyType": {keyType}}, "en")] = fmt.Sprintf(`{"data":[{"id":%d,"keyType":%q}]}`, i, keyType)
+	}
+	for key, data := range seeds {
+		if err := cache.Seed(key, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := tvdb.Client{Language: "en", Cache: cache}
+	s, err := c.GetSeriesFull(context.Background(), 1, tvdb.Include{Actors: true, Summary: true, Images: true, EpisodeDetails: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "One", s.SeriesName)
+	assert.Len(t, s.Actors, 1)
+	assert.Equal(t, "2", s.Summary.AiredEpisodes)
+	assert.Len(t, s.Images, 5)
+	if assert.Len(t, s.Episodes, 2) {
+		assert.Equal(t, []string{"Director"}, s.GetEpisode(1, 1).Directors)
+		assert.Equal(t, []string{"Writer"}, s.GetEpisode(1, 2).Writers)
+	}
+
+	s, err = c.GetSeriesFull(context.Background(), 1, tvdb.Include{})
+	assert.Nil(t, err)
+	assert.Empty(t, s.Episodes)
+	assert.Empty(t, s.Images)
+}
@@ -0,0 +1,197 @@
+package tvdb
+
+import (
+	"container/list"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores the raw json responses of the TVDB api. Set Client.Cache to
+// serve repeated GET requests without hitting the network. Implementations
+// must be safe for concurrent use.
+type Cache interface {
+	// Get returns the data stored with key, if present and not expired.
+	Get(key string) ([]byte, bool)
+	// Set stores data with key for the ttl duration.
+	Set(key string, data []byte, ttl time.Duration)
+	// Delete removes the data stored with key.
+	Delete(key string)
+}
+
+// EvictableCache is a Cache whose entries can be evicted by key prefix, used
+// by the Invalidator to remove the responses of the updated series.
+type EvictableCache interface {
+	Cache
+	// EvictPrefix removes the entries with the key starting with prefix that
+	// were stored before the time, and returns their keys.
+	EvictPrefix(prefix string, before time.Time) []string
+}
+
+// Resource types of the TVDB api endpoints, used as keys of Client.CacheTTL.
+const (
+	CacheLanguages = "languages"
+	CacheSearch    = "search"
+	CacheSeries    = "series"
+	CacheEpisodes  = "episodes"
+	CacheActors    = "actors"
+	CacheImages    = "images"
+	CacheUpdates   = "updates"
+)
+
+// DefaultCacheTTL holds the time to live of the cached responses by resource
+// type. It can be overridden per client with Client.CacheTTL. The updates are
+// not cached because they are used to know when the other resources change.
+var DefaultCacheTTL = map[string]time.Duration{
+	CacheLanguages: 7 * 24 * time.Hour,
+	CacheSearch:    10 * time.Minute,
+	CacheSeries:    6 * time.Hour,
+	CacheEpisodes:  6 * time.Hour,
+	CacheActors:    24 * time.Hour,
+	CacheImages:    24 * time.Hour,
+	CacheUpdates:   0,
+}
+
+// CacheKey returns the key used to cache the response of a GET request to
+// the api endpoint path with the query params, in the language. Keys start
+// with the path, so all the keys of a series start with /series/{id}.
+func CacheKey(path string, params url.Values, language string) string {
+	return path + "?" + params.Encode() + "#" + language
+}
+
+// resourceType returns the resource type of an api endpoint path, or an
+// empty string if the responses of the endpoint must not be cached.
+func resourceType(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch parts[0] {
+	case "languages":
+		return CacheLanguages
+	case "search":
+		return CacheSearch
+	case "updated":
+		return CacheUpdates
+	case "episodes":
+		return CacheEpisodes
+	case "series":
+		if len(parts) < 3 {
+			return CacheSeries
+		}
+		switch parts[2] {
+		case "episodes":
+			return CacheEpisodes
+		case "actors":
+			return CacheActors
+		case "images":
+			return CacheImages
+		}
+	}
+	return ""
+}
+
+// cacheTTL returns the time to live of the responses of the api endpoint
+// path. Zero means that the responses are not cached.
+func (c *Client) cacheTTL(path string) time.Duration {
+	resource := resourceType(path)
+	if resource == "" {
+		return 0
+	}
+	if ttl, ok := c.CacheTTL[resource]; ok {
+		return ttl
+	}
+	return DefaultCacheTTL[resource]
+}
+
+// MemoryCache is an in-memory Cache that keeps at most a fixed number of
+// entries, evicting the least recently used ones.
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	lru      *list.List
+}
+
+type memoryEntry struct {
+	key     string
+	data    []byte
+	stored  time.Time
+	expires time.Time
+}
+
+// NewMemoryCache returns a MemoryCache that holds at most capacity entries.
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Get implements the Cache interface.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*memoryEntry)
+	if time.Now().After(entry.expires) {
+		m.remove(el)
+		return nil, false
+	}
+	m.lru.MoveToFront(el)
+	return entry.data, true
+}
+
+// Set implements the Cache interface.
+func (m *MemoryCache) Set(key string, data []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	entry := &memoryEntry{key: key, data: data, stored: now, expires: now.Add(ttl)}
+	if el, ok := m.entries[key]; ok {
+		el.Value = entry
+		m.lru.MoveToFront(el)
+		return
+	}
+	m.entries[key] = m.lru.PushFront(entry)
+	for m.capacity > 0 && m.lru.Len() > m.capacity {
+		m.remove(m.lru.Back())
+	}
+}
+
+// Delete implements the Cache interface.
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.entries[key]; ok {
+		m.remove(el)
+	}
+}
+
+// EvictPrefix implements the EvictableCache interface.
+func (m *MemoryCache) EvictPrefix(prefix string, before time.Time) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0)
+	for key, el := range m.entries {
+		if strings.HasPrefix(key, prefix) && el.Value.(*memoryEntry).stored.Before(before) {
+			m.remove(el)
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Len returns the number of entries in the cache, expired ones included.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lru.Len()
+}
+
+func (m *MemoryCache) remove(el *list.Element) {
+	m.lru.Remove(el)
+	delete(m.entries, el.Value.(*memoryEntry).key)
+}
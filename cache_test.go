@@ -0,0 +1,137 @@
+package tvdb_test
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pioz/tvdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCache(t *testing.T) {
+	cache := tvdb.NewMemoryCache(2)
+	cache.Set("a", []byte("1"), time.Hour)
+	cache.Set("b", []byte("2"), time.Hour)
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+	cache.Set("c", []byte("3"), time.Hour)
+	_, ok = cache.Get("b")
+	assert.False(t, ok, "least recently used entry is evicted")
+	data, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), data)
+	cache.Delete("a")
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+	cache.Set("d", []byte("4"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = cache.Get("d")
+	assert.False(t, ok, "expired entry is not returned")
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestClientCache(t *testing.T) {
+	cache := tvdb.NewMemoryCache(10)
+	cache.Set(tvdb.CacheKey("/languages", nil, "it"), []byte(`{"data":[{"id":15,"abbreviation":"it"}]}`), time.Hour)
+	c := tvdb.Client{Language: "it", Cache: cache}
+	languages, err := c.GetLanguages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "it", languages[0].Abbreviation)
+
+	s := tvdb.Series{ID: 121361}
+	cache.Set(tvdb.CacheKey(fmt.Sprintf("/series/%d", s.ID), nil, "it"), []byte(`{"data":{"id":121361,"seriesName":"Il Trono di Spade"}}`), time.Hour)
+	err = c.GetSeries(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Il Trono di Spade", s.SeriesName)
+}
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := tvdb.NewDiskCache(dir, false)
+	key := tvdb.CacheKey("/series/1/episodes/query", url.Values{"page": {"1"}}, "en")
+	cache.Set(key, []byte(`{"data":[]}`), time.Hour)
+	data, ok := cache.Get(key)
+	assert.True(t, ok)
+	assert.Equal(t, `{"data":[]}`, string(data))
+	assert.FileExists(t, filepath.Join(dir, "en", "series", "1", "episodes", "query", "page=1.json"))
+	cache.Delete(key)
+	_, ok = cache.Get(key)
+	assert.False(t, ok)
+
+	cache.Set(key, []byte(`{"data":[]}`), -time.Hour)
+	_, ok = cache.Get(key)
+	assert.False(t, ok, "expired entry is not returned")
+	_, ok = tvdb.NewDiskCache(dir, true).Get(key)
+	assert.True(t, ok, "expired entry is returned in offline mode")
+	assert.NotNil(t, cache.Seed(key, []byte("not json")))
+}
+
+func TestClientOffline(t *testing.T) {
+	cache := tvdb.NewDiskCache(t.TempDir(), true)
+	err := cache.Seed(tvdb.CacheKey("/search/series", url.Values{"name": {"Game of Thrones"}}, "en"), []byte(`{"data":[{"id":121361,"seriesName":"Game of Thrones"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := tvdb.Client{Language: "en", Cache: cache}
+	assert.Nil(t, c.Login())
+	series, err := c.BestSearch("Game of Thrones")
+	assert.Nil(t, err)
+	assert.Equal(t, 121361, series.ID)
+	_, err = c.GetLanguages()
+	assert.Equal(t, tvdb.ErrCacheMiss, err)
+}
+
+func TestCacheEvictPrefix(t *testing.T) {
+	caches := []tvdb.EvictableCache{tvdb.NewMemoryCache(10), tvdb.NewDiskCache(t.TempDir(), false)}
+	for _, cache := range caches {
+		series := tvdb.CacheKey("/series/1", nil, "en")
+		episodes := tvdb.CacheKey("/series/1/episodes", url.Values{"page": {"1"}}, "en")
+		other := tvdb.CacheKey("/series/10", nil, "en")
+		for _, key := range []string{series, episodes, other} {
+			cache.Set(key, []byte(`{}`), time.Hour)
+		}
+		assert.Empty(t, cache.EvictPrefix("/series/1?", time.Now().Add(-time.Hour)), "entries stored after the time are kept")
+		assert.Equal(t, []string{series}, cache.EvictPrefix("/series/1?", time.Now().Add(time.Second)))
+		assert.Equal(t, []string{episodes}, cache.EvictPrefix("/series/1/", time.Now().Add(time.Second)))
+		_, ok := cache.Get(other)
+		assert.True(t, ok)
+	}
+}
+
+type offlineMemoryCache struct {
+	*tvdb.MemoryCache
+}
+
+func (offlineMemoryCache) Offline() bool { return true }
+
+func TestInvalidator(t *testing.T) {
+	cache := offlineMemoryCache{tvdb.NewMemoryCache(10)}
+	series := tvdb.CacheKey("/series/1", nil, "en")
+	cache.Set(series, []byte(`{"data":{"id":1}}`), time.Hour)
+	since := time.Now().Add(-time.Hour).Unix()
+	updated := time.Now().Add(time.Second).Unix()
+	cache.Set(tvdb.CacheKey("/updated/query", url.Values{"fromTime": {fmt.Sprint(since)}}, "en"), []byte(fmt.Sprintf(`{"data":[{"id":1,"lastUpdated":%d}]}`, updated)), time.Hour)
+
+	c := tvdb.Client{Language: "en", Cache: cache}
+	inv := tvdb.NewInvalidator(&c, time.Minute)
+	inv.Since = since
+	var evicted []string
+	inv.OnEvict = func(u tvdb.Update, keys []string) { evicted = keys }
+	updates, err := inv.Poll()
+	assert.Nil(t, err)
+	assert.Len(t, updates, 1)
+	assert.Equal(t, []string{series}, evicted)
+	_, ok := cache.Get(series)
+	assert.False(t, ok)
+	assert.Greater(t, inv.Since, since)
+
+	_, err = tvdb.NewInvalidator(&tvdb.Client{}, time.Minute).Poll()
+	assert.Equal(t, tvdb.ErrCacheNotEvictable, err)
+}
@@ -12,6 +12,7 @@ package tvdb
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -19,7 +20,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
+	"time"
 )
 
 // Client does the work of perform the REST requests to the TVDB api endpoints.
@@ -33,16 +34,31 @@ type Client struct {
 	// The language with which you want to obtain the data (if not set english is
 	// used)
 	Language string
-	token    string
-	client   http.Client
+	// Cache of the GET responses, responses are not cached if nil.
+	Cache Cache
+	// Time to live of the cached responses by resource type, overrides
+	// DefaultCacheTTL.
+	CacheTTL map[string]time.Duration
+	// Limits the rate of the requests sent to the api, requests are not
+	// limited if nil. Responses served by the cache are not limited.
+	RateLimiter *RateLimiter
+	token       string
+	client      http.Client
+	// Context of the requests waiting for the rate limiter, set on the copies
+	// of the client used by the methods with a context.
+	ctx context.Context
 }
 
 // BaseURL where the TVDB api is accessible.
 const BaseURL string = "https://api.thetvdb.com"
 
 // Login is used to retrieve a valid token which will be used to make any other
-// requests to the TVDB api. The token is stored in the Client struct.
+// requests to the TVDB api. The token is stored in the Client struct. If the
+// client cache is offline (see OfflineCache) Login does nothing.
 func (c *Client) Login() error {
+	if c.offline() {
+		return nil
+	}
 	loginData := map[string]string{"apikey": c.Apikey}
 	if c.Userkey != "" {
 		loginData["userkey"] = c.Userkey
@@ -113,25 +129,12 @@ func (c *Client) SearchByZap2itID(q string) ([]Series, error) {
 	return c.search(url.Values{"zap2itId": {q}})
 }
 
-// BestSearch returns the best Series based on the name (q).
+// BestSearch returns the best Series based on the name (q). Results are ranked
+// by BestSearchWithHints without hints. If no series is found ErrNoResults is
+// returned.
 func (c *Client) BestSearch(q string) (Series, error) {
-	res, err := c.SearchByName(q)
-	if err != nil {
-		return Series{}, err
-	}
-	for _, series := range res {
-		if strings.ToLower(series.SeriesName) == strings.ToLower(q) {
-			return series, nil
-		}
-	}
-	for _, serie := range res {
-		for _, alias := range serie.Aliases {
-			if strings.ToLower(alias) == strings.ToLower(q) {
-				return serie, nil
-			}
-		}
-	}
-	return res[0], nil
+	series, _, err := c.BestSearchWithHints(q, SearchHints{})
+	return series, err
 }
 
 // GetSeries retrieve all series's fields. If a series is returned from a search
@@ -227,9 +230,37 @@ func (c *Client) GetSeriesEpisodes(s *Series, params url.Values) error {
 		}
 	}
 	s.Episodes = episodes
+	s.resetEpisodeIndex()
 	return nil
 }
 
+// GetEpisodeByAirDate retrieve the episodes of the series identified by
+// seriesID first aired on the date. Daily shows can air more than one episode
+// on the same day, so all of them are returned sorted by aired season and
+// episode number. If there are no episodes aired on the date
+// ErrEpisodeNotFound is returned.
+func (c *Client) GetEpisodeByAirDate(seriesID int, date time.Time) ([]Episode, error) {
+	s := Series{ID: seriesID}
+	err := c.GetSeriesEpisodes(&s, url.Values{"firstAired": {date.Format("2006-01-02")}})
+	if HaveCodeError(404, err) {
+		// The api responds 404 when no episode matches the filter.
+		return nil, ErrEpisodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The filter may be ignored by the api, so episodes are filtered again.
+	found := s.EpisodesOn(date)
+	if len(found) == 0 {
+		return nil, ErrEpisodeNotFound
+	}
+	episodes := make([]Episode, 0, len(found))
+	for _, ep := range found {
+		episodes = append(episodes, *ep)
+	}
+	return episodes, nil
+}
+
 // GetSeriesSummary retrieve the summary of the episodes and seasons available
 // for the series. Summary is accessible from series.Summary struct field.
 func (c *Client) GetSeriesSummary(s *Series) error {
@@ -305,18 +336,28 @@ func (c *Client) getSeriesImages(s *Series, keyType string) error {
 	if s.Empty() {
 		return errors.New("the serie is empty")
 	}
-	resp, err := c.performGETRequest(fmt.Sprintf("/series/%d/images/query", s.ID), url.Values{"keyType": {keyType}})
+	images, err := c.seriesImages(s.ID, keyType)
 	if err != nil {
 		return err
 	}
+	s.Images = images
+	return nil
+}
+
+// seriesImages retrieves the images of type keyType of the series identified
+// by id.
+func (c *Client) seriesImages(id int, keyType string) ([]Image, error) {
+	resp, err := c.performGETRequest(fmt.Sprintf("/series/%d/images/query", id), url.Values{"keyType": {keyType}})
+	if err != nil {
+		return nil, err
+	}
 	defer resp.Body.Close()
 	data := new(imagesAPIResponse)
 	err = parseResponse(resp.Body, &data)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	s.Images = data.Data
-	return nil
+	return data.Data, nil
 }
 
 func (c *Client) search(params url.Values) ([]Series, error) {
@@ -334,6 +375,42 @@ func (c *Client) search(params url.Values) ([]Series, error) {
 }
 
 func (c *Client) performGETRequest(path string, params url.Values) (*http.Response, error) {
+	key := CacheKey(path, params, c.Language)
+	if c.offline() {
+		if data, ok := c.Cache.Get(key); ok {
+			return bodyResponse(data), nil
+		}
+		return nil, ErrCacheMiss
+	}
+	ttl := c.cacheTTL(path)
+	cached := c.Cache != nil && ttl > 0
+	if cached {
+		if data, ok := c.Cache.Get(key); ok {
+			return bodyResponse(data), nil
+		}
+	}
+	if err := c.wait(); err != nil {
+		return nil, err
+	}
+	// Identical concurrent requests are coalesced in a single http request.
+	data, err := inflight.do(c.token+" "+key, func() ([]byte, error) {
+		resp, err := c.doGETRequest(path, params)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		return io.ReadAll(resp.Body)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cached {
+		c.Cache.Set(key, data, ttl)
+	}
+	return bodyResponse(data), nil
+}
+
+func (c *Client) doGETRequest(path string, params url.Values) (*http.Response, error) {
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", BaseURL, path), nil)
 	req.URL.RawQuery = params.Encode()
 	if err != nil {
@@ -353,6 +430,12 @@ func (c *Client) performGETRequest(path string, params url.Values) (*http.Respon
 }
 
 func (c *Client) performPOSTRequest(path string, params map[string]string) (*http.Response, error) {
+	if c.offline() {
+		return nil, ErrCacheMiss
+	}
+	if err := c.wait(); err != nil {
+		return nil, err
+	}
 	jsonMarshal, _ := json.Marshal(params)
 	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", BaseURL, path), bytes.NewBuffer(jsonMarshal))
 	if err != nil {
@@ -371,6 +454,42 @@ func (c *Client) performPOSTRequest(path string, params map[string]string) (*htt
 	return resp, err
 }
 
+// wait waits for the rate limiter, if any.
+func (c *Client) wait() error {
+	if c.RateLimiter == nil {
+		return nil
+	}
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return c.RateLimiter.Wait(ctx)
+}
+
+// withContext returns a copy of the client that waits for the rate limiter
+// until ctx is done.
+func (c *Client) withContext(ctx context.Context) *Client {
+	cc := *c
+	cc.ctx = ctx
+	return &cc
+}
+
+// offline returns true if the client cache is an OfflineCache in offline
+// mode.
+func (c *Client) offline() bool {
+	oc, ok := c.Cache.(OfflineCache)
+	return ok && oc.Offline()
+}
+
+// bodyResponse returns a successful response with data as body.
+func bodyResponse(data []byte) *http.Response {
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(data)),
+	}
+}
+
 func parseResponse(body io.ReadCloser, data interface{}) error {
 	// b, err := ioutil.ReadAll(body)
 	// if err != nil {
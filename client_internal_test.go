@@ -0,0 +1,33 @@
+package tvdb
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestGetEpisodeByAirDateNotFound(t *testing.T) {
+	c := Client{Language: "en", client: http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader(`{"Error":"No results for your query"}`))}, nil
+	})}}
+	_, err := c.GetEpisodeByAirDate(1, time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, ErrEpisodeNotFound, err)
+}
+
+func TestBestSearchNotFound(t *testing.T) {
+	c := Client{Language: "en", client: http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader(`{"Error":"Resource not found"}`))}, nil
+	})}}
+	_, err := c.BestSearch("nothing")
+	assert.Equal(t, ErrNoResults, err)
+}
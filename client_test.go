@@ -4,6 +4,7 @@ import (
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/pioz/tvdb"
 	"github.com/stretchr/testify/assert"
@@ -14,6 +15,7 @@ func TestClientLogin(t *testing.T) {
 }
 
 func TestClientLoginFail(t *testing.T) {
+	skipOffline(t)
 	c := tvdb.Client{Apikey: "WRONG APIKEY"}
 	err := c.Login()
 	if err == nil {
@@ -23,6 +25,7 @@ func TestClientLoginFail(t *testing.T) {
 }
 
 func TestClientRefreshToken(t *testing.T) {
+	skipOffline(t)
 	c := login(t)
 	err := c.RefreshToken()
 	if err != nil {
@@ -41,6 +44,7 @@ func TestClientGetLanguages(t *testing.T) {
 }
 
 func TestClientGetUpdates(t *testing.T) {
+	skipOffline(t)
 	c := login(t)
 	updates, err := c.GetUpdates(1594509621) //Get all updates
 	assert.Nil(t, err)
@@ -48,6 +52,7 @@ func TestClientGetUpdates(t *testing.T) {
 }
 
 func TestClientGetUpdatesOldDate(t *testing.T) {
+	skipOffline(t)
 	c := login(t)
 	updates, err := c.GetUpdates(0) //Get all updates
 	assert.Nil(t, err)
@@ -84,7 +89,7 @@ func TestClientBestSearch(t *testing.T) {
 	assert.False(t, tvdb.HaveCodeError(404, err))
 	assert.Equal(t, "Game of Thrones", res.SeriesName)
 	res, err = c.BestSearch("kajdsfhasdkjhfsadkjhfasdkh")
-	assert.True(t, tvdb.HaveCodeError(404, err))
+	assert.Equal(t, tvdb.ErrNoResults, err)
 }
 
 func TestClientGetSeries(t *testing.T) {
@@ -164,6 +169,36 @@ func TestClientGetEpisode(t *testing.T) {
 	assert.Equal(t, "tt1480055", s.GetEpisode(1, 1).ImdbID)
 }
 
+func TestGetEpisodeByAirDate(t *testing.T) {
+	cache := tvdb.NewDiskCache(t.TempDir(), true)
+	episodes := `{"data":[
+		{"id":2,"airedSeason":1,"airedEpisodeNumber":2,"firstAired":"2024-03-05"},
+		{"id":1,"airedSeason":1,"airedEpisodeNumber":1,"firstAired":"2024-03-05"},
+		{"id":3,"airedSeason":1,"airedEpisodeNumber":3,"firstAired":"2024-03-06"}
+	]}`
+	for _, date := range []string{"2024-03-05", "2024-03-06", "2024-03-07"} {
+		key := tvdb.CacheKey("/series/1/episodes/query", url.Values{"firstAired": {date}, "page": {"1"}}, "en")
+		if err := cache.Seed(key, []byte(episodes)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := tvdb.Client{Language: "en", Cache: cache}
+
+	found, err := c.GetEpisodeByAirDate(1, time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC))
+	if assert.NoError(t, err) && assert.Len(t, found, 1) {
+		assert.Equal(t, 3, found[0].ID)
+	}
+	// More episodes aired on the same day
+	found, err = c.GetEpisodeByAirDate(1, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC))
+	if assert.NoError(t, err) && assert.Len(t, found, 2) {
+		assert.Equal(t, 1, found[0].ID)
+		assert.Equal(t, 2, found[1].ID)
+	}
+	// The filter is ignored by the api
+	_, err = c.GetEpisodeByAirDate(1, time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, tvdb.ErrEpisodeNotFound, err)
+}
+
 func TestSeriesGetSeasonEpisodes(t *testing.T) {
 	c := login(t)
 	s := getSerie(t, c, "Game of Thrones")
@@ -182,8 +217,15 @@ func TestSeriesBannerURL(t *testing.T) {
 	assert.Equal(t, "https://thetvdb.com/banners/graphical/5c8c227dbd218.jpg", s.BannerURL())
 }
 
+// login returns a logged in client. If TVDB_CACHE_DIR is set responses are
+// recorded in that directory, and with TVDB_OFFLINE=1 they are served only
+// from there without network. Without TVDB_APIKEY the tests run offline with
+// the responses in testdata/cache.
 func login(t *testing.T) tvdb.Client {
 	c := tvdb.Client{Apikey: os.Getenv("TVDB_APIKEY"), Language: "en"}
+	if dir, offline := testCache(); dir != "" {
+		c.Cache = tvdb.NewDiskCache(dir, offline)
+	}
 	err := c.Login()
 	if err != nil {
 		t.Fatal(err)
@@ -191,6 +233,26 @@ func login(t *testing.T) tvdb.Client {
 	return c
 }
 
+// testCache returns the directory of the recorded responses, empty if the
+// responses are not recorded, and if the tests run offline.
+func testCache() (string, bool) {
+	dir, offline := os.Getenv("TVDB_CACHE_DIR"), os.Getenv("TVDB_OFFLINE") != ""
+	if os.Getenv("TVDB_APIKEY") == "" {
+		if dir == "" {
+			dir = "testdata/cache"
+		}
+		offline = true
+	}
+	return dir, offline
+}
+
+// skipOffline skips the tests that can't be served from the cache.
+func skipOffline(t *testing.T) {
+	if _, offline := testCache(); offline {
+		t.Skip("requires network")
+	}
+}
+
 func getSerie(t *testing.T, c tvdb.Client, name string) tvdb.Series {
 	series, err := c.BestSearch(name)
 	if err != nil {
@@ -0,0 +1,165 @@
+// Command tvdb-watch is a daemon that watches a list of TVDB series and posts
+// a JSON event to a set of webhooks when something happens to them: a new
+// episode is announced, the air date of an episode changes, an episode airs
+// or a series ends.
+//
+// Usage:
+//
+//	tvdb-watch [-config path] [-once]
+//
+// The config file is a JSON file like:
+//
+//	{
+//	  "apikey": "...", "userkey": "...", "username": "...", "language": "en",
+//	  "series": [121361, 81189],
+//	  "webhooks": [{"url": "https://example.com/hook", "secret": "..."}],
+//	  "interval": "15m",
+//	  "timezone": "America/New_York",
+//	  "state": "/var/lib/tvdb-watch"
+//	}
+//
+// Credentials can also be set with the TVDB_APIKEY, TVDB_USERKEY and
+// TVDB_USERNAME environment variables. The series are polled every interval
+// (default 15 minutes) using the TVDB updates, so only the changed series are
+// retrieved again. The air times of the episodes are computed in the timezone
+// (default local).
+//
+// The series are synced with the sync package: the last known version of the
+// series, the checkpoint of the sync and the events not yet delivered are
+// saved in the state directory, so the daemon can be restarted without
+// repeating events. The first run only records the current state of the
+// series.
+//
+// Events are posted with the X-TVDB-Event header holding the event type, the
+// X-TVDB-Delivery header holding the event id, that is the same for every
+// retry, and, if the webhook has a secret, the X-TVDB-Signature header
+// holding sha256= followed by the hex HMAC-SHA256 of the body keyed with the
+// secret. Failed deliveries are retried with exponential backoff and, if they
+// keep failing, at the next polls.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/pioz/tvdb"
+	"github.com/pioz/tvdb/sync"
+)
+
+// config is the content of the config file.
+type config struct {
+	Apikey   string    `json:"apikey"`
+	Userkey  string    `json:"userkey"`
+	Username string    `json:"username"`
+	Language string    `json:"language"`
+	Series   []int     `json:"series"`
+	Webhooks []webhook `json:"webhooks"`
+	Interval string    `json:"interval"`
+	Timezone string    `json:"timezone"`
+	State    string    `json:"state"`
+}
+
+func main() {
+	configPath := flag.String("config", "tvdb-watch.json", "config file path")
+	once := flag.Bool("once", false, "poll once and exit")
+	flag.Parse()
+	log.SetPrefix("tvdb-watch: ")
+
+	w, interval, err := setup(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	for {
+		if err := w.poll(ctx); err != nil {
+			log.Print(err)
+		}
+		if *once {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// setup reads the config file and returns the watcher and the poll interval.
+func setup(path string) (*watcher, time.Duration, error) {
+	var cfg config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, 0, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if len(cfg.Series) == 0 {
+		return nil, 0, errors.New("no series to watch")
+	}
+	if len(cfg.Webhooks) == 0 {
+		return nil, 0, errors.New("no webhooks")
+	}
+	interval := 15 * time.Minute
+	if cfg.Interval != "" {
+		if interval, err = time.ParseDuration(cfg.Interval); err != nil {
+			return nil, 0, fmt.Errorf("invalid interval: %w", err)
+		}
+	}
+	loc := time.Local
+	if cfg.Timezone != "" {
+		if loc, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, 0, err
+		}
+	}
+	if cfg.State == "" {
+		cfg.State = "tvdb-watch-state"
+	}
+	store, err := tvdb.OpenDirStore(cfg.State)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	c := &tvdb.Client{
+		Apikey:   firstNonEmpty(os.Getenv("TVDB_APIKEY"), cfg.Apikey),
+		Userkey:  firstNonEmpty(os.Getenv("TVDB_USERKEY"), cfg.Userkey),
+		Username: firstNonEmpty(os.Getenv("TVDB_USERNAME"), cfg.Username),
+		Language: firstNonEmpty(cfg.Language, "en"),
+	}
+	if c.Apikey == "" {
+		return nil, 0, fmt.Errorf("missing api key, set the TVDB_APIKEY environment variable or the config file %s", path)
+	}
+	if err := c.Login(); err != nil {
+		return nil, 0, err
+	}
+	syncer := sync.New(c, store)
+	syncer.Name = checkpointName
+	w := &watcher{
+		syncer:   syncer,
+		series:   cfg.Series,
+		location: loc,
+		outbox:   &outbox{path: filepath.Join(cfg.State, "outbox.json"), webhooks: cfg.Webhooks},
+		loggedIn: time.Now(),
+	}
+	return w, interval, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pioz/tvdb"
+	"github.com/pioz/tvdb/sync"
+)
+
+// Event types.
+const (
+	eventEpisodeAnnounced = "episode.announced"
+	eventAirDateChanged   = "episode.airdate_changed"
+	eventEpisodeAired     = "episode.aired"
+	eventSeriesEnded      = "series.ended"
+)
+
+// checkpointName is the name of the checkpoint of the watcher in the store.
+const checkpointName = "watch"
+
+// tokenRefresh is the age of the api token after which it is refreshed. The
+// tokens expire after 24 hours.
+const tokenRefresh = 12 * time.Hour
+
+// event is the body posted to the webhooks.
+type event struct {
+	// ID of the event, the same for every delivery of the event.
+	ID         string        `json:"id"`
+	Type       string        `json:"type"`
+	Time       time.Time     `json:"time"`
+	SeriesID   int           `json:"seriesId"`
+	SeriesName string        `json:"seriesName"`
+	Episode    *tvdb.Episode `json:"episode,omitempty"`
+	// Previous first aired date of an episode.airdate_changed event.
+	OldAirDate string `json:"oldAirDate,omitempty"`
+	// Air time of an episode.aired event.
+	AirTime *time.Time `json:"airTime,omitempty"`
+}
+
+// watcher polls the watched series and queues the events in the outbox.
+type watcher struct {
+	// Syncer of the watched series, with the checkpoint named checkpointName.
+	syncer   *sync.Syncer
+	series   []int
+	location *time.Location
+	outbox   *outbox
+	loggedIn time.Time
+}
+
+// poll syncs the watched series, queues the events of their changes and of
+// the episodes aired since the last poll and delivers them. The series that
+// changed are saved by the sync before the events are queued.
+func (w *watcher) poll(ctx context.Context) error {
+	if time.Since(w.loggedIn) > tokenRefresh {
+		if err := w.syncer.Client.RefreshToken(); err != nil {
+			if err := w.syncer.Client.Login(); err != nil {
+				return err
+			}
+		}
+		w.loggedIn = time.Now()
+	}
+	last, err := w.syncer.Store.GetCheckpoint(checkpointName)
+	if err != nil && !errors.Is(err, tvdb.ErrNotStored) {
+		return err
+	}
+	report, syncErr := w.syncer.Sync(ctx, w.series)
+	now := time.Now()
+
+	var (
+		events []event
+		errs   = []error{syncErr}
+	)
+	for _, change := range report.Changes {
+		if change.New {
+			continue
+		}
+		s, err := tvdb.LoadSeries(w.syncer.Store, change.SeriesID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("series %d: %w", change.SeriesID, err))
+			continue
+		}
+		events = append(events, diffEvents(change.SeriesDiff, s, now)...)
+	}
+
+	// Episodes aired between the last and this sync. If the sync failed
+	// before saving its checkpoint they are found at the next poll.
+	if last.Time != 0 && report.Checkpoint.Time != 0 {
+		since, until := time.Unix(last.Time, 0), time.Unix(report.Checkpoint.Time, 0)
+		for _, id := range report.Checkpoint.Series {
+			s, err := tvdb.LoadSeries(w.syncer.Store, id)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("series %d: %w", id, err))
+				continue
+			}
+			events = append(events, w.airedEvents(s, since, until)...)
+		}
+	}
+
+	if err := w.outbox.add(events); err != nil {
+		return err
+	}
+	errs = append(errs, w.outbox.flush(ctx))
+	return errors.Join(errs...)
+}
+
+// airedEvents returns the events of the episodes of the series aired after
+// since and until now.
+func (w *watcher) airedEvents(s tvdb.Series, since, now time.Time) []event {
+	var events []event
+	for i := range s.Episodes {
+		ep := s.Episodes[i]
+		t, ok := s.AirTime(&ep, w.location)
+		if !ok || !t.After(since) || t.After(now) {
+			continue
+		}
+		events = append(events, event{
+			ID:         fmt.Sprintf("%s-%d-%s", eventEpisodeAired, ep.ID, ep.FirstAired),
+			Type:       eventEpisodeAired,
+			Time:       now,
+			SeriesID:   s.ID,
+			SeriesName: s.SeriesName,
+			Episode:    &ep,
+			AirTime:    &t,
+		})
+	}
+	return events
+}
+
+// diffEvents returns the events of the changes d of a series. new is the
+// changed version of the series.
+func diffEvents(d tvdb.SeriesDiff, new tvdb.Series, now time.Time) []event {
+	var events []event
+	for i := range d.Episodes.Added {
+		ep := d.Episodes.Added[i]
+		events = append(events, event{
+			ID:         fmt.Sprintf("%s-%d", eventEpisodeAnnounced, ep.ID),
+			Type:       eventEpisodeAnnounced,
+			Time:       now,
+			SeriesID:   new.ID,
+			SeriesName: new.SeriesName,
+			Episode:    &ep,
+		})
+	}
+	for _, change := range d.Episodes.Changed {
+		firstAired, ok := change.Field("firstAired")
+		if !ok {
+			continue
+		}
+		oldAirDate, ok := firstAired.Old.(string)
+		ep := new.GetEpisodeByID(change.ID)
+		if !ok || ep == nil {
+			continue
+		}
+		epCopy := *ep
+		events = append(events, event{
+			ID:         fmt.Sprintf("%s-%d-%s", eventAirDateChanged, ep.ID, ep.FirstAired),
+			Type:       eventAirDateChanged,
+			Time:       now,
+			SeriesID:   new.ID,
+			SeriesName: new.SeriesName,
+			Episode:    &epCopy,
+			OldAirDate: oldAirDate,
+		})
+	}
+	if _, ok := d.Field("status"); ok && new.Status == "Ended" {
+		events = append(events, event{
+			ID:         fmt.Sprintf("%s-%d", eventSeriesEnded, new.ID),
+			Type:       eventSeriesEnded,
+			Time:       now,
+			SeriesID:   new.ID,
+			SeriesName: new.SeriesName,
+		})
+	}
+	return events
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	gosync "sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pioz/tvdb"
+	"github.com/pioz/tvdb/sync"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffEvents(t *testing.T) {
+	old := tvdb.Series{ID: 1, SeriesName: "Series", Status: "Continuing", Episodes: []tvdb.Episode{
+		{ID: 11, AiredSeason: 1, AiredEpisodeNumber: 1, FirstAired: "2020-01-01"},
+	}}
+	new := tvdb.Series{ID: 1, SeriesName: "Series", Status: "Ended", Episodes: []tvdb.Episode{
+		{ID: 11, AiredSeason: 1, AiredEpisodeNumber: 1, FirstAired: "2020-01-08"},
+		{ID: 12, AiredSeason: 1, AiredEpisodeNumber: 2},
+	}}
+	events := diffEvents(tvdb.Diff(old, new), new, time.Now())
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, eventEpisodeAnnounced, events[0].Type)
+		assert.Equal(t, 12, events[0].Episode.ID)
+		assert.Equal(t, eventAirDateChanged, events[1].Type)
+		assert.Equal(t, "2020-01-01", events[1].OldAirDate)
+		assert.Equal(t, eventSeriesEnded, events[2].Type)
+	}
+
+	w := watcher{location: time.UTC}
+	now := time.Date(2020, 1, 8, 12, 0, 0, 0, time.UTC)
+	aired := w.airedEvents(new, now.Add(-24*time.Hour), now)
+	if assert.Len(t, aired, 1) {
+		assert.Equal(t, 11, aired[0].Episode.ID)
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, sign("secret", body), r.Header.Get("X-TVDB-Signature"))
+		assert.Equal(t, eventSeriesEnded, r.Header.Get("X-TVDB-Event"))
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	o := &outbox{
+		path:     filepath.Join(t.TempDir(), "outbox.json"),
+		webhooks: []webhook{{URL: server.URL, Secret: "secret"}},
+		delay:    time.Millisecond,
+	}
+	assert.Nil(t, o.add([]event{{ID: "series.ended-1", Type: eventSeriesEnded, Time: time.Now(), SeriesID: 1}}))
+	e := event{ID: "series.ended-1", Type: eventSeriesEnded, Time: time.Now(), SeriesID: 1}
+	assert.Nil(t, o.add([]event{e}), "queued events are ignored")
+	state, _ := o.load()
+	assert.Len(t, state.Queue, 1)
+	assert.Nil(t, o.flush(context.Background()))
+	assert.Equal(t, int32(2), calls.Load(), "failed delivery is retried")
+	state, _ = o.load()
+	assert.Empty(t, state.Queue)
+
+	assert.Nil(t, o.add([]event{e}), "delivered events are ignored")
+	state, _ = o.load()
+	assert.Empty(t, state.Queue)
+}
+
+func TestWatcherPoll(t *testing.T) {
+	cache := tvdb.NewDiskCache(t.TempDir(), true)
+	seed := func(path string, params url.Values, data string) {
+		if err := cache.Seed(tvdb.CacheKey(path, params, "en"), []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	seed("/series/1", nil, `{"data":{"id":1,"seriesName":"Series","status":"Continuing"}}`)
+	seed("/series/1/episodes/query", url.Values{"page": {"1"}}, `{"data":[{"id":11,"airedSeason":1,"airedEpisodeNumber":1}]}`)
+	seed("/series/1/actors", nil, `{"data":[]}`)
+	for _, keyType := range []string{"fanart", "poster", "season", "seasonwide", "series"} {
+		seed("/series/1/images/query", url.Values{"keyType": {keyType}}, `{"data":[]}`)
+	}
+
+	var (
+		mu       gosync.Mutex
+		received []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, r.Header.Get("X-TVDB-Event"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	store, err := tvdb.OpenDirStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncer := sync.New(&tvdb.Client{Language: "en", Cache: cache}, store)
+	syncer.Name = checkpointName
+	w := &watcher{
+		syncer:   syncer,
+		series:   []int{1},
+		location: time.UTC,
+		outbox:   &outbox{path: filepath.Join(dir, "outbox.json"), webhooks: []webhook{{URL: server.URL}}},
+		loggedIn: time.Now(),
+	}
+	assert.Nil(t, w.poll(context.Background()))
+	assert.Empty(t, received, "the first poll records the state")
+	cursor, err := store.GetCheckpoint(checkpointName)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1}, cursor.Series)
+
+	seed("/updated/query", url.Values{"fromTime": {strconv.FormatInt(cursor.Time, 10)}}, `{"data":[{"id":1}]}`)
+	seed("/series/1/episodes/query", url.Values{"page": {"1"}}, `{"data":[{"id":11,"airedSeason":1,"airedEpisodeNumber":1},{"id":12,"airedSeason":1,"airedEpisodeNumber":2}]}`)
+	assert.Nil(t, w.poll(context.Background()))
+	assert.Equal(t, []string{eventEpisodeAnnounced}, received)
+}
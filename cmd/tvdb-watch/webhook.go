@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// maxEventAge is the age after which an event that can't be delivered is
+// dropped. Events removed from the queue are remembered for the same time, so
+// an event queued again in the meanwhile is ignored.
+const maxEventAge = 7 * 24 * time.Hour
+
+// webhook is an URL where the events are posted. If Secret is set the body is
+// signed with it.
+type webhook struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// delivery is an event to post to a webhook.
+type delivery struct {
+	URL   string `json:"url"`
+	Event event  `json:"event"`
+}
+
+// outbox is the queue of the deliveries not yet completed, persisted in a
+// json file together with the deliveries recently removed from the queue.
+type outbox struct {
+	path     string
+	webhooks []webhook
+	// Http client used to post the events (default with a 30 seconds
+	// timeout).
+	client *http.Client
+	// Number of retries of a failed post (default 3) and delay before the
+	// first retry, doubled at every retry (default 1 second).
+	retries int
+	delay   time.Duration
+}
+
+// outboxState is the content of the outbox file.
+type outboxState struct {
+	Queue []delivery `json:"queue"`
+	// Time when the deliveries removed from the queue, because delivered or
+	// dropped, were removed, by delivery key.
+	Done map[string]time.Time `json:"done"`
+}
+
+// key identifies the delivery of an event to a webhook.
+func (d delivery) key() string {
+	return d.URL + " " + d.Event.ID
+}
+
+// errPermanent marks a delivery error that won't be fixed retrying.
+var errPermanent = errors.New("permanent error")
+
+// add queues the events for every webhook. Deliveries already queued or
+// recently removed from the queue are ignored.
+func (o *outbox) add(events []event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	state, err := o.load()
+	if err != nil {
+		return err
+	}
+	queued := make(map[string]bool, len(state.Queue))
+	for _, d := range state.Queue {
+		queued[d.key()] = true
+	}
+	for _, e := range events {
+		for _, hook := range o.webhooks {
+			d := delivery{URL: hook.URL, Event: e}
+			if _, done := state.Done[d.key()]; done || queued[d.key()] {
+				continue
+			}
+			queued[d.key()] = true
+			state.Queue = append(state.Queue, d)
+		}
+	}
+	return o.save(state)
+}
+
+// flush posts the queued events. Deliveries that fail with a temporary error
+// stay in the queue, the others are removed.
+func (o *outbox) flush(ctx context.Context) error {
+	state, err := o.load()
+	if err != nil || len(state.Queue) == 0 {
+		return err
+	}
+	secrets := make(map[string]string, len(o.webhooks))
+	for _, hook := range o.webhooks {
+		secrets[hook.URL] = hook.Secret
+	}
+	var (
+		failed []delivery
+		errs   []error
+	)
+	now := time.Now()
+	for _, d := range state.Queue {
+		secret, ok := secrets[d.URL]
+		if !ok {
+			// The webhook has been removed from the config.
+			continue
+		}
+		err := ctx.Err()
+		if err == nil {
+			err = o.deliver(ctx, d, secret)
+		}
+		switch {
+		case err == nil:
+		case errors.Is(err, errPermanent):
+			errs = append(errs, err)
+		case time.Since(d.Event.Time) > maxEventAge:
+			errs = append(errs, fmt.Errorf("drop event %s: %w", d.Event.ID, err))
+		default:
+			errs = append(errs, err)
+			failed = append(failed, d)
+			continue
+		}
+		state.Done[d.key()] = now
+	}
+	for key, t := range state.Done {
+		if now.Sub(t) > maxEventAge {
+			delete(state.Done, key)
+		}
+	}
+	state.Queue = failed
+	if err := o.save(state); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// deliver posts the event of d, retrying with exponential backoff network
+// errors and 429 and 5xx responses.
+func (o *outbox) deliver(ctx context.Context, d delivery, secret string) error {
+	body, err := json.Marshal(d.Event)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPermanent, err)
+	}
+	client := o.client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	retries := o.retries
+	if retries <= 0 {
+		retries = 3
+	}
+	delay := o.delay
+	if delay <= 0 {
+		delay = time.Second
+	}
+	for attempt := 0; ; attempt++ {
+		err = post(ctx, client, d, secret, body)
+		if err == nil || errors.Is(err, errPermanent) || attempt >= retries {
+			if err != nil {
+				err = fmt.Errorf("deliver %s to %s: %w", d.Event.ID, d.URL, err)
+			}
+			return err
+		}
+		log.Printf("deliver %s to %s: %s, retrying", d.Event.ID, d.URL, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func post(ctx context.Context, client *http.Client, d delivery, secret string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPermanent, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-TVDB-Event", d.Event.Type)
+	req.Header.Set("X-TVDB-Delivery", d.Event.ID)
+	if secret != "" {
+		req.Header.Set("X-TVDB-Signature", sign(secret, body))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("status code %d", resp.StatusCode)
+	}
+	return fmt.Errorf("%w: status code %d", errPermanent, resp.StatusCode)
+}
+
+// sign returns the signature of the body: sha256= followed by the hex
+// HMAC-SHA256 of the body keyed with secret.
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (o *outbox) load() (outboxState, error) {
+	state := outboxState{Done: make(map[string]time.Time)}
+	data, err := os.ReadFile(o.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("invalid outbox %s: %w", o.path, err)
+	}
+	if state.Done == nil {
+		state.Done = make(map[string]time.Time)
+	}
+	return state, nil
+}
+
+// save writes the state to a temporary file renamed to the outbox path, so
+// the outbox is never left partially written.
+func (o *outbox) save(state outboxState) error {
+	if state.Queue == nil {
+		state.Queue = []delivery{}
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(o.path), ".outbox-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), o.path)
+}
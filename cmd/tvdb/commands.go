@@ -0,0 +1,274 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pioz/tvdb"
+)
+
+const (
+	searchUsage    = "search [flags] <name>"
+	seriesUsage    = "series [flags] <series id>"
+	episodesUsage  = "episodes [flags] <series id>"
+	episodeUsage   = "episode [flags] <episode id>"
+	actorsUsage    = "actors [flags] <series id>"
+	imagesUsage    = "images [flags] <series id>"
+	updatesUsage   = "updates [flags]"
+	languagesUsage = "languages [flags]"
+)
+
+func runSearch(args []string) error {
+	flags, opts := newFlagSet("search")
+	imdb := flags.Bool("imdb", false, "search by IMDB id")
+	zap2it := flags.Bool("zap2it", false, "search by Zap2it id")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		return fmt.Errorf("usage: tvdb %s", searchUsage)
+	}
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	q := strings.Join(flags.Args(), " ")
+	var res []tvdb.Series
+	switch {
+	case *imdb:
+		res, err = c.SearchByImdbID(q)
+	case *zap2it:
+		res, err = c.SearchByZap2itID(q)
+	default:
+		res, err = c.SearchByName(q)
+	}
+	if err != nil && !tvdb.HaveCodeError(404, err) {
+		return err
+	}
+	t := &table{v: res, header: []string{"ID", "NAME", "FIRST AIRED", "NETWORK", "STATUS"}}
+	for _, s := range res {
+		t.add(s.ID, s.SeriesName, s.FirstAired, s.Network, s.Status)
+	}
+	return t.print(opts.format)
+}
+
+func runSeries(args []string) error {
+	flags, opts := newFlagSet("series")
+	flags.Parse(args)
+	id, err := idArg(flags, seriesUsage)
+	if err != nil {
+		return err
+	}
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	s := tvdb.Series{ID: id}
+	if err := c.GetSeries(&s); err != nil {
+		return err
+	}
+	return fields(s,
+		"ID", s.ID,
+		"Name", s.SeriesName,
+		"Aliases", strings.Join(s.Aliases, ", "),
+		"Status", s.Status,
+		"First aired", s.FirstAired,
+		"Network", s.Network,
+		"Airs", strings.TrimSpace(s.AirsDayOfWeek+" "+s.AirsTime),
+		"Runtime", s.Runtime,
+		"Genre", strings.Join(s.Genre, ", "),
+		"Rating", s.Rating,
+		"Site rating", fmt.Sprintf("%.1f (%d votes)", s.SiteRating, s.SiteRatingCount),
+		"IMDB", s.ImdbID,
+		"Zap2it", s.Zap2itID,
+		"Banner", s.BannerURL(),
+		"Overview", s.Overview,
+	).print(opts.format)
+}
+
+func runEpisodes(args []string) error {
+	flags, opts := newFlagSet("episodes")
+	season := flags.Int("season", -1, "aired season number (default all seasons)")
+	flags.Parse(args)
+	id, err := idArg(flags, episodesUsage)
+	if err != nil {
+		return err
+	}
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	s := tvdb.Series{ID: id}
+	var params url.Values
+	if *season >= 0 {
+		params = url.Values{"airedSeason": {strconv.Itoa(*season)}}
+	}
+	if err := c.GetSeriesEpisodes(&s, params); err != nil {
+		return err
+	}
+	sort.SliceStable(s.Episodes, func(i, j int) bool {
+		a, b := s.Episodes[i], s.Episodes[j]
+		return a.AiredSeason < b.AiredSeason || (a.AiredSeason == b.AiredSeason && a.AiredEpisodeNumber < b.AiredEpisodeNumber)
+	})
+	t := &table{v: s.Episodes, header: []string{"ID", "SEASON", "EPISODE", "NAME", "FIRST AIRED"}}
+	for _, ep := range s.Episodes {
+		t.add(ep.ID, ep.AiredSeason, ep.AiredEpisodeNumber, ep.EpisodeName, ep.FirstAired)
+	}
+	return t.print(opts.format)
+}
+
+func runEpisode(args []string) error {
+	flags, opts := newFlagSet("episode")
+	flags.Parse(args)
+	id, err := idArg(flags, episodeUsage)
+	if err != nil {
+		return err
+	}
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	ep := tvdb.Episode{ID: id}
+	if err := c.GetEpisode(&ep); err != nil {
+		return err
+	}
+	return fields(ep,
+		"ID", ep.ID,
+		"Series ID", ep.SeriesID,
+		"Name", ep.EpisodeName,
+		"Season", ep.AiredSeason,
+		"Episode", ep.AiredEpisodeNumber,
+		"Absolute", ep.AbsoluteNumber,
+		"DVD", fmt.Sprintf("%d x %g", ep.DvdSeason, ep.DvdEpisodeNumber),
+		"First aired", ep.FirstAired,
+		"Directors", strings.Join(ep.Directors, ", "),
+		"Writers", strings.Join(ep.Writers, ", "),
+		"Guest stars", strings.Join(ep.GuestStars, ", "),
+		"Site rating", fmt.Sprintf("%.1f (%d votes)", ep.SiteRating, ep.SiteRatingCount),
+		"IMDB", ep.ImdbID,
+		"Thumbnail", ep.ThumbnailURL(),
+		"Overview", ep.Overview,
+	).print(opts.format)
+}
+
+func runActors(args []string) error {
+	flags, opts := newFlagSet("actors")
+	flags.Parse(args)
+	id, err := idArg(flags, actorsUsage)
+	if err != nil {
+		return err
+	}
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	s := tvdb.Series{ID: id}
+	if err := c.GetSeriesActors(&s); err != nil {
+		return err
+	}
+	sort.SliceStable(s.Actors, func(i, j int) bool {
+		return s.Actors[i].SortOrder < s.Actors[j].SortOrder
+	})
+	t := &table{v: s.Actors, header: []string{"ID", "NAME", "ROLE", "IMAGE"}}
+	for _, a := range s.Actors {
+		t.add(a.ID, a.Name, a.Role, a.ImageURL())
+	}
+	return t.print(opts.format)
+}
+
+func runImages(args []string) error {
+	flags, opts := newFlagSet("images")
+	keyType := flags.String("type", "poster", "image type: fanart, poster, season, seasonwide or series")
+	flags.Parse(args)
+	id, err := idArg(flags, imagesUsage)
+	if err != nil {
+		return err
+	}
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	s := tvdb.Series{ID: id}
+	switch *keyType {
+	case "fanart":
+		err = c.GetSeriesFanartImages(&s)
+	case "poster":
+		err = c.GetSeriesPosterImages(&s)
+	case "season":
+		err = c.GetSeriesSeasonImages(&s)
+	case "seasonwide":
+		err = c.GetSeriesSeasonwideImages(&s)
+	case "series":
+		err = c.GetSeriesSeriesImages(&s)
+	default:
+		return fmt.Errorf("unknown image type %q", *keyType)
+	}
+	if err != nil {
+		return err
+	}
+	t := &table{v: s.Images, header: []string{"ID", "TYPE", "SUBKEY", "RESOLUTION", "RATING", "URL"}}
+	for _, img := range s.Images {
+		t.add(img.ID, img.KeyType, img.SubKey, img.Resolution, fmt.Sprintf("%.1f (%d)", img.RatingsInfo.Average, img.RatingsInfo.Count), img.URL())
+	}
+	return t.print(opts.format)
+}
+
+func runUpdates(args []string) error {
+	flags, opts := newFlagSet("updates")
+	since := flags.Duration("since", 24*time.Hour, "list the series updated in this period")
+	from := flags.Int64("from", 0, "list the series updated since this epoch time (overrides -since)")
+	flags.Parse(args)
+	epoch := *from
+	if epoch == 0 {
+		epoch = time.Now().Add(-*since).Unix()
+	}
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	updates, err := c.GetUpdates(int(epoch))
+	if err != nil {
+		return err
+	}
+	t := &table{v: updates, header: []string{"ID", "LAST UPDATED"}}
+	for _, u := range updates {
+		t.add(u.ID, time.Unix(int64(u.LastUpdated), 0).UTC().Format(time.RFC3339))
+	}
+	return t.print(opts.format)
+}
+
+func runLanguages(args []string) error {
+	flags, opts := newFlagSet("languages")
+	flags.Parse(args)
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	languages, err := c.GetLanguages()
+	if err != nil {
+		return err
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i].Abbreviation < languages[j].Abbreviation
+	})
+	t := &table{v: languages, header: []string{"ID", "CODE", "ENGLISH NAME", "NAME"}}
+	for _, l := range languages {
+		t.add(l.ID, l.Abbreviation, l.EnglishName, l.Name)
+	}
+	return t.print(opts.format)
+}
+
+// idArg returns the id passed as the only argument of the command.
+func idArg(flags *flag.FlagSet, usage string) (int, error) {
+	if flags.NArg() != 1 {
+		return 0, fmt.Errorf("usage: tvdb %s", usage)
+	}
+	id, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		return 0, fmt.Errorf("invalid id %q", flags.Arg(0))
+	}
+	return id, nil
+}
@@ -0,0 +1,185 @@
+// Command tvdb is a command line client for the TVDB api.
+//
+// Usage:
+//
+//	tvdb <command> [flags] [arguments]
+//
+// Credentials are read from the TVDB_APIKEY, TVDB_USERKEY and TVDB_USERNAME
+// environment variables or from a JSON config file (default
+// $XDG_CONFIG_HOME/tvdb/config.json) like:
+//
+//	{"apikey": "...", "userkey": "...", "username": "...", "language": "en"}
+//
+// Environment variables take precedence over the config file. Results are
+// printed as a table, JSON or CSV according to the -format flag. Run tvdb help
+// to list the commands.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pioz/tvdb"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"search":    {searchUsage + ": search series by name, IMDB or Zap2it id", runSearch},
+	"series":    {seriesUsage + ": show a series", runSeries},
+	"episodes":  {episodesUsage + ": list the episodes of a series", runEpisodes},
+	"episode":   {episodeUsage + ": show an episode", runEpisode},
+	"actors":    {actorsUsage + ": list the actors of a series", runActors},
+	"images":    {imagesUsage + ": list the images of a series", runImages},
+	"updates":   {updatesUsage + ": list the series updated since a time", runUpdates},
+	"languages": {languagesUsage + ": list the available languages", runLanguages},
+	"rename":    {renameUsage + ": rename and move episode files", runRename},
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "help" || os.Args[1] == "-h" {
+		usage()
+		return
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "tvdb: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "tvdb: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: tvdb <command> [flags] [arguments]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun tvdb <command> -h for the flags of a command.")
+}
+
+// options holds the flags shared by all the commands.
+type options struct {
+	config   string
+	language string
+	format   string
+}
+
+// newFlagSet returns the flag set of a command with the shared flags.
+func newFlagSet(name string) (*flag.FlagSet, *options) {
+	opts := new(options)
+	opts.format = "table"
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.StringVar(&opts.config, "config", defaultConfigPath(), "config file path")
+	flags.StringVar(&opts.language, "lang", "", "language of the results (default from config or en)")
+	flags.Var((*formatFlag)(&opts.format), "format", "output format: table, json or csv")
+	return flags, opts
+}
+
+// formatFlag is the -format flag, checked when the flags are parsed so that
+// an unknown format fails before any request.
+type formatFlag string
+
+func (f *formatFlag) String() string { return string(*f) }
+
+func (f *formatFlag) Set(s string) error {
+	switch s {
+	case "table", "json", "csv":
+		*f = formatFlag(s)
+		return nil
+	}
+	return fmt.Errorf("unknown format %q", s)
+}
+
+// config is the content of the config file.
+type config struct {
+	Apikey   string `json:"apikey"`
+	Userkey  string `json:"userkey"`
+	Username string `json:"username"`
+	Language string `json:"language"`
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tvdb", "config.json")
+}
+
+func loadConfig(path string) (config, error) {
+	var cfg config
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// newClient returns a logged in client with the credentials read from the
+// environment or from the config file.
+func newClient(opts *options) (*tvdb.Client, error) {
+	c, err := clientConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Login(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// clientConfig returns a client, not logged in, with the credentials read
+// from the environment or from the config file.
+func clientConfig(opts *options) (*tvdb.Client, error) {
+	cfg, err := loadConfig(opts.config)
+	if err != nil {
+		return nil, err
+	}
+	c := &tvdb.Client{
+		Apikey:   firstNonEmpty(os.Getenv("TVDB_APIKEY"), cfg.Apikey),
+		Userkey:  firstNonEmpty(os.Getenv("TVDB_USERKEY"), cfg.Userkey),
+		Username: firstNonEmpty(os.Getenv("TVDB_USERNAME"), cfg.Username),
+		Language: firstNonEmpty(opts.language, os.Getenv("TVDB_LANGUAGE"), cfg.Language, "en"),
+	}
+	if c.Apikey == "" {
+		return nil, fmt.Errorf("missing api key, set the TVDB_APIKEY environment variable or the config file %s", opts.config)
+	}
+	return c, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
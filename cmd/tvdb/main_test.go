@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDArg(t *testing.T) {
+	flags, _ := newFlagSet("series")
+	flags.Parse([]string{"121361"})
+	id, err := idArg(flags, seriesUsage)
+	assert.NoError(t, err)
+	assert.Equal(t, 121361, id)
+
+	flags, _ = newFlagSet("series")
+	flags.Parse([]string{"got"})
+	_, err = idArg(flags, seriesUsage)
+	assert.EqualError(t, err, `invalid id "got"`)
+
+	flags, _ = newFlagSet("series")
+	flags.Parse([]string{"1", "2"})
+	_, err = idArg(flags, seriesUsage)
+	assert.EqualError(t, err, "usage: tvdb "+seriesUsage)
+}
+
+func TestFormatFlag(t *testing.T) {
+	flags, opts := newFlagSet("series")
+	assert.Equal(t, "table", opts.format)
+	assert.NoError(t, flags.Set("format", "csv"))
+	assert.Equal(t, "csv", opts.format)
+	assert.Error(t, flags.Set("format", "xml"))
+	assert.Equal(t, "csv", opts.format)
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := loadConfig(filepath.Join(dir, "missing.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, config{}, cfg)
+
+	path := filepath.Join(dir, "config.json")
+	os.WriteFile(path, []byte(`{"apikey":"key","userkey":"user key","username":"user","language":"it"}`), 0o600)
+	cfg, err = loadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, config{Apikey: "key", Userkey: "user key", Username: "user", Language: "it"}, cfg)
+
+	os.WriteFile(path, []byte(`{"apikey":`), 0o600)
+	_, err = loadConfig(path)
+	assert.Error(t, err)
+}
+
+func TestClientConfig(t *testing.T) {
+	for _, name := range []string{"TVDB_APIKEY", "TVDB_USERKEY", "TVDB_USERNAME", "TVDB_LANGUAGE"} {
+		t.Setenv(name, "")
+	}
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"apikey":"key","userkey":"user key","username":"user","language":"it"}`), 0o600)
+
+	c, err := clientConfig(&options{config: path})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "key", c.Apikey)
+		assert.Equal(t, "user key", c.Userkey)
+		assert.Equal(t, "user", c.Username)
+		assert.Equal(t, "it", c.Language)
+	}
+
+	// The environment takes precedence over the config file and the -lang
+	// flag over both.
+	t.Setenv("TVDB_APIKEY", "env key")
+	t.Setenv("TVDB_LANGUAGE", "de")
+	c, err = clientConfig(&options{config: path})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "env key", c.Apikey)
+		assert.Equal(t, "user", c.Username)
+		assert.Equal(t, "de", c.Language)
+	}
+	c, err = clientConfig(&options{config: path, language: "fr"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "fr", c.Language)
+	}
+
+	t.Setenv("TVDB_APIKEY", "")
+	t.Setenv("TVDB_LANGUAGE", "")
+	_, err = clientConfig(&options{config: filepath.Join(t.TempDir(), "missing.json")})
+	assert.ErrorContains(t, err, "missing api key")
+	_, err = clientConfig(&options{})
+	assert.Error(t, err)
+}
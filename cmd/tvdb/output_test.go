@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testTable() *table {
+	type row struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	t := &table{v: []row{{1, "One"}, {22, "Two, with comma"}}, header: []string{"ID", "NAME"}}
+	t.add(1, "One")
+	t.add(22, "Two, with comma")
+	return t
+}
+
+func TestTableWrite(t *testing.T) {
+	var buf bytes.Buffer
+	if assert.NoError(t, testTable().write(&buf, "table")) {
+		assert.Equal(t, "ID  NAME\n1   One\n22  Two, with comma\n", buf.String())
+	}
+
+	buf.Reset()
+	if assert.NoError(t, testTable().write(&buf, "csv")) {
+		assert.Equal(t, "ID,NAME\n1,One\n22,\"Two, with comma\"\n", buf.String())
+	}
+
+	buf.Reset()
+	if assert.NoError(t, testTable().write(&buf, "json")) {
+		assert.JSONEq(t, `[{"id":1,"name":"One"},{"id":22,"name":"Two, with comma"}]`, buf.String())
+	}
+
+	assert.EqualError(t, testTable().write(&buf, "xml"), `unknown format "xml"`)
+}
+
+func TestTableWriteMultiline(t *testing.T) {
+	var buf bytes.Buffer
+	tab := fields(nil, "Overview", "First line\nsecond line")
+	if assert.NoError(t, tab.write(&buf, "table")) {
+		assert.Equal(t, "FIELD     VALUE\nOverview  First line second line\n", buf.String())
+	}
+}
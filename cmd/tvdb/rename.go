@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pioz/tvdb"
+	"github.com/pioz/tvdb/rename"
+)
+
+const renameUsage = "rename [flags] <dir>"
+
+func runRename(args []string) error {
+	flags, opts := newFlagSet("rename")
+	template := flags.String("template", rename.DefaultTemplate, "destination path template")
+	dest := flags.String("dest", "", "destination directory (default the source directory)")
+	dryRun := flags.Bool("dry-run", false, "print the renames without applying them")
+	minConfidence := flags.Float64("min-confidence", 0.8, "minimum match confidence")
+	undoLog := flags.String("undo-log", "tvdb-rename.log", "undo log path")
+	undo := flags.Bool("undo", false, "revert the renames recorded in the undo log")
+	flags.Parse(args)
+
+	if *undo {
+		return rename.Undo(*undoLog)
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: tvdb %s", renameUsage)
+	}
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	// Files of the same series search the series and retrieve its episodes
+	// again, the cache serves them after the first file.
+	c.Cache = tvdb.NewMemoryCache(1000)
+	r := rename.Renamer{
+		Resolver:      c,
+		Template:      *template,
+		Dest:          *dest,
+		DryRun:        *dryRun,
+		MinConfidence: *minConfidence,
+		UndoLog:       *undoLog,
+	}
+	ops, err := r.Run(flags.Arg(0))
+	for _, op := range ops {
+		switch {
+		case op.Err != nil:
+			fmt.Fprintf(os.Stderr, "skip %s: %s\n", op.From, op.Err)
+		case op.From != op.To:
+			fmt.Printf("%s -> %s\n", op.From, op.To)
+		}
+	}
+	return err
+}
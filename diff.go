@@ -0,0 +1,230 @@
+package tvdb
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// diffIgnoredFields are the json names of the fields not compared by Diff and
+// DiffEpisodes, because they change without an edit of the record.
+var diffIgnoredFields = map[string]bool{
+	"lastUpdated":     true,
+	"lastUpdatedBy":   true,
+	"siteRating":      true,
+	"siteRatingCount": true,
+}
+
+// FieldChange is the change of a field of a record. Field is the json name of
+// the field.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// EpisodeChange holds the changed fields of an episode present in both the
+// old and the new version of a series.
+type EpisodeChange struct {
+	ID int `json:"id"`
+	// Aired season, episode number and name of the new version.
+	AiredSeason        int           `json:"airedSeason"`
+	AiredEpisodeNumber int           `json:"airedEpisodeNumber"`
+	EpisodeName        string        `json:"episodeName"`
+	Fields             []FieldChange `json:"fields"`
+}
+
+// Field returns the change of the field with the json name, if changed.
+func (c *EpisodeChange) Field(name string) (FieldChange, bool) {
+	return findField(c.Fields, name)
+}
+
+// Renamed returns true if the episode name changed.
+func (c *EpisodeChange) Renamed() bool {
+	_, ok := c.Field("episodeName")
+	return ok
+}
+
+// AirDateChanged returns true if the first aired date changed.
+func (c *EpisodeChange) AirDateChanged() bool {
+	_, ok := c.Field("firstAired")
+	return ok
+}
+
+// EpisodesDiff is the difference between two lists of episodes, matched by
+// id. Episodes are sorted by aired season and episode number.
+type EpisodesDiff struct {
+	Added   []Episode       `json:"added,omitempty"`
+	Removed []Episode       `json:"removed,omitempty"`
+	Changed []EpisodeChange `json:"changed,omitempty"`
+}
+
+// Empty returns true if the episodes didn't change.
+func (d *EpisodesDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// ActorChange holds the changed fields of an actor present in both the old
+// and the new version of a series.
+type ActorChange struct {
+	ID     int           `json:"id"`
+	Name   string        `json:"name"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// SeriesDiff is the difference between two versions of a series.
+type SeriesDiff struct {
+	SeriesID int `json:"seriesId"`
+	// Changed fields of the series record.
+	Fields        []FieldChange `json:"fields,omitempty"`
+	Episodes      EpisodesDiff  `json:"episodes"`
+	AddedActors   []Actor       `json:"addedActors,omitempty"`
+	RemovedActors []Actor       `json:"removedActors,omitempty"`
+	ChangedActors []ActorChange `json:"changedActors,omitempty"`
+	AddedImages   []Image       `json:"addedImages,omitempty"`
+	RemovedImages []Image       `json:"removedImages,omitempty"`
+}
+
+// Field returns the change of the series field with the json name, if
+// changed.
+func (d *SeriesDiff) Field(name string) (FieldChange, bool) {
+	return findField(d.Fields, name)
+}
+
+// Empty returns true if nothing changed.
+func (d *SeriesDiff) Empty() bool {
+	return len(d.Fields) == 0 && d.Episodes.Empty() &&
+		len(d.AddedActors) == 0 && len(d.RemovedActors) == 0 && len(d.ChangedActors) == 0 &&
+		len(d.AddedImages) == 0 && len(d.RemovedImages) == 0
+}
+
+// Diff returns the field level changes from the old to the new version of a
+// series: the changed fields of the series record and the added, removed and
+// changed episodes, actors and images. Related records are matched by id.
+// Images are only added or removed. The fields that change without an edit,
+// like lastUpdated and siteRating, are ignored.
+func Diff(old, new Series) SeriesDiff {
+	d := SeriesDiff{
+		SeriesID: new.ID,
+		Fields:   diffFields(old, new),
+		Episodes: DiffEpisodes(old.Episodes, new.Episodes),
+	}
+
+	oldActors := make(map[int]Actor, len(old.Actors))
+	for _, a := range old.Actors {
+		oldActors[a.ID] = a
+	}
+	for _, a := range new.Actors {
+		oldActor, ok := oldActors[a.ID]
+		delete(oldActors, a.ID)
+		if !ok {
+			d.AddedActors = append(d.AddedActors, a)
+		} else if fields := diffFields(oldActor, a); len(fields) > 0 {
+			d.ChangedActors = append(d.ChangedActors, ActorChange{ID: a.ID, Name: a.Name, Fields: fields})
+		}
+	}
+	for _, a := range old.Actors {
+		if _, ok := oldActors[a.ID]; ok {
+			d.RemovedActors = append(d.RemovedActors, a)
+		}
+	}
+
+	oldImages := make(map[int]bool, len(old.Images))
+	for _, img := range old.Images {
+		oldImages[img.ID] = true
+	}
+	newImages := make(map[int]bool, len(new.Images))
+	for _, img := range new.Images {
+		newImages[img.ID] = true
+		if !oldImages[img.ID] {
+			d.AddedImages = append(d.AddedImages, img)
+		}
+	}
+	for _, img := range old.Images {
+		if !newImages[img.ID] {
+			d.RemovedImages = append(d.RemovedImages, img)
+		}
+	}
+	return d
+}
+
+// DiffEpisodes returns the episodes added, removed and changed from the old
+// to the new list of episodes. Episodes are matched by id. The fields that
+// change without an edit, like lastUpdated and siteRating, are ignored.
+func DiffEpisodes(old, new []Episode) EpisodesDiff {
+	var d EpisodesDiff
+	oldEpisodes := make(map[int]Episode, len(old))
+	for _, ep := range old {
+		oldEpisodes[ep.ID] = ep
+	}
+	newEpisodes := make(map[int]bool, len(new))
+	for _, ep := range new {
+		newEpisodes[ep.ID] = true
+		oldEp, ok := oldEpisodes[ep.ID]
+		if !ok {
+			d.Added = append(d.Added, ep)
+		} else if fields := diffFields(oldEp, ep); len(fields) > 0 {
+			d.Changed = append(d.Changed, EpisodeChange{
+				ID:                 ep.ID,
+				AiredSeason:        ep.AiredSeason,
+				AiredEpisodeNumber: ep.AiredEpisodeNumber,
+				EpisodeName:        ep.EpisodeName,
+				Fields:             fields,
+			})
+		}
+	}
+	for _, ep := range old {
+		if !newEpisodes[ep.ID] {
+			d.Removed = append(d.Removed, ep)
+		}
+	}
+	sortEpisodes(d.Added)
+	sortEpisodes(d.Removed)
+	sort.SliceStable(d.Changed, func(i, j int) bool {
+		a, b := d.Changed[i], d.Changed[j]
+		if a.AiredSeason != b.AiredSeason {
+			return a.AiredSeason < b.AiredSeason
+		}
+		return a.AiredEpisodeNumber < b.AiredEpisodeNumber
+	})
+	return d
+}
+
+func sortEpisodes(episodes []Episode) {
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return airedLess(&episodes[i], &episodes[j])
+	})
+}
+
+// diffFields returns the changes of the fields with a json name between two
+// values of the same struct type.
+func diffFields(old, new interface{}) []FieldChange {
+	oldValue, newValue := reflect.ValueOf(old), reflect.ValueOf(new)
+	t := oldValue.Type()
+	var changes []FieldChange
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "" || name == "-" || diffIgnoredFields[name] {
+			continue
+		}
+		a, b := oldValue.Field(i), newValue.Field(i)
+		// Nil and empty slices are equal, the api returns both.
+		if a.Kind() == reflect.Slice && a.Len() == 0 && b.Len() == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			changes = append(changes, FieldChange{Field: name, Old: a.Interface(), New: b.Interface()})
+		}
+	}
+	return changes
+}
+
+func findField(fields []FieldChange, name string) (FieldChange, bool) {
+	for _, f := range fields {
+		if f.Field == name {
+			return f, true
+		}
+	}
+	return FieldChange{}, false
+}
@@ -0,0 +1,66 @@
+package tvdb_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pioz/tvdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	old := tvdb.Series{
+		ID:          1,
+		SeriesName:  "Series",
+		Status:      "Continuing",
+		Genre:       []string{},
+		LastUpdated: 100,
+		Episodes: []tvdb.Episode{
+			{ID: 11, AiredSeason: 1, AiredEpisodeNumber: 1, EpisodeName: "Pilot", FirstAired: "2020-01-01"},
+			{ID: 12, AiredSeason: 1, AiredEpisodeNumber: 2, EpisodeName: "TBA", FirstAired: "2020-01-08"},
+			{ID: 13, AiredSeason: 1, AiredEpisodeNumber: 3},
+		},
+		Actors: []tvdb.Actor{{ID: 1, Name: "Actor", Role: "Hero"}, {ID: 2, Name: "Gone"}},
+		Images: []tvdb.Image{{ID: 1}},
+	}
+	new := old
+	new.Status = "Ended"
+	new.Genre = nil
+	new.LastUpdated = 200
+	new.Episodes = []tvdb.Episode{
+		{ID: 14, AiredSeason: 1, AiredEpisodeNumber: 3, EpisodeName: "Finale"},
+		{ID: 12, AiredSeason: 1, AiredEpisodeNumber: 2, EpisodeName: "The Return", FirstAired: "2020-01-15", SiteRating: 8},
+		{ID: 11, AiredSeason: 1, AiredEpisodeNumber: 1, EpisodeName: "Pilot", FirstAired: "2020-01-01", LastUpdated: 200},
+	}
+	new.Actors = []tvdb.Actor{{ID: 1, Name: "Actor", Role: "Villain"}, {ID: 3, Name: "Newcomer"}}
+	new.Images = []tvdb.Image{{ID: 1}, {ID: 2}}
+
+	d := tvdb.Diff(old, new)
+	assert.False(t, d.Empty())
+	assert.Equal(t, []tvdb.FieldChange{{Field: "status", Old: "Continuing", New: "Ended"}}, d.Fields)
+	if assert.Len(t, d.Episodes.Added, 1) && assert.Len(t, d.Episodes.Removed, 1) {
+		assert.Equal(t, 14, d.Episodes.Added[0].ID)
+		assert.Equal(t, 13, d.Episodes.Removed[0].ID)
+	}
+	if assert.Len(t, d.Episodes.Changed, 1) {
+		change := d.Episodes.Changed[0]
+		assert.Equal(t, 12, change.ID)
+		assert.True(t, change.Renamed())
+		assert.True(t, change.AirDateChanged())
+		firstAired, _ := change.Field("firstAired")
+		assert.Equal(t, "2020-01-08", firstAired.Old)
+		assert.Len(t, change.Fields, 2, "site rating is ignored")
+	}
+	assert.Equal(t, "Newcomer", d.AddedActors[0].Name)
+	assert.Equal(t, "Gone", d.RemovedActors[0].Name)
+	assert.Equal(t, []tvdb.ActorChange{{ID: 1, Name: "Actor", Fields: []tvdb.FieldChange{{Field: "role", Old: "Hero", New: "Villain"}}}}, d.ChangedActors)
+	assert.Len(t, d.AddedImages, 1)
+	assert.Empty(t, d.RemovedImages)
+
+	data, err := json.Marshal(d)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `{"field":"status","old":"Continuing","new":"Ended"}`)
+
+	same := tvdb.Diff(old, old)
+	assert.True(t, same.Empty())
+}
@@ -0,0 +1,204 @@
+package tvdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrStoreVersion is returned by OpenDirStore when the directory has been
+// written by a newer version of the package.
+var ErrStoreVersion = errors.New("unsupported store version")
+
+// dirStoreMigrations upgrade the layout of a DirStore directory: the function
+// at index i migrates a directory from version i+1 to version i+2. Append a
+// migration when the layout changes.
+var dirStoreMigrations = []func(dir string) error{}
+
+// dirStoreVersion is the current layout version of a DirStore directory.
+var dirStoreVersion = len(dirStoreMigrations) + 1
+
+// DirStore is a Store that saves every record in a json file in a directory:
+//
+//	dir/version
+//	dir/series/121361.json
+//	dir/episodes/121361.json
+//	dir/actors/121361.json
+//	dir/images/121361.json
+//	dir/checkpoints/sync.json
+//
+// The version file holds the layout version of the directory, that is
+// upgraded when the directory is opened by a newer version of the package.
+type DirStore struct {
+	dir string
+}
+
+// OpenDirStore returns a DirStore that saves the records in dir, creating the
+// directory if it doesn't exist and migrating it if it has been written by an
+// older version of the package.
+func OpenDirStore(dir string) (*DirStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	d := &DirStore{dir: dir}
+	version, err := d.version()
+	if err != nil {
+		return nil, err
+	}
+	if version > dirStoreVersion {
+		return nil, fmt.Errorf("%w %d", ErrStoreVersion, version)
+	}
+	for ; version < dirStoreVersion; version++ {
+		if err := dirStoreMigrations[version-1](dir); err != nil {
+			return nil, fmt.Errorf("migrate store to version %d: %w", version+1, err)
+		}
+		if err := d.setVersion(version + 1); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// GetSeries implements the Store interface.
+func (d *DirStore) GetSeries(id int) (Series, error) {
+	var s Series
+	err := d.get("series", strconv.Itoa(id), &s)
+	return s, err
+}
+
+// PutSeries implements the Store interface.
+func (d *DirStore) PutSeries(s Series) error {
+	s.Episodes, s.Actors, s.Images, s.index = nil, nil, nil, nil
+	return d.put("series", strconv.Itoa(s.ID), s)
+}
+
+// DeleteSeries implements the Store interface.
+func (d *DirStore) DeleteSeries(id int) error {
+	return d.delete("series", strconv.Itoa(id))
+}
+
+// GetEpisodes implements the Store interface.
+func (d *DirStore) GetEpisodes(seriesID int) ([]Episode, error) {
+	var episodes []Episode
+	err := d.get("episodes", strconv.Itoa(seriesID), &episodes)
+	return episodes, err
+}
+
+// PutEpisodes implements the Store interface.
+func (d *DirStore) PutEpisodes(seriesID int, episodes []Episode) error {
+	return d.put("episodes", strconv.Itoa(seriesID), episodes)
+}
+
+// DeleteEpisodes implements the Store interface.
+func (d *DirStore) DeleteEpisodes(seriesID int) error {
+	return d.delete("episodes", strconv.Itoa(seriesID))
+}
+
+// GetActors implements the Store interface.
+func (d *DirStore) GetActors(seriesID int) ([]Actor, error) {
+	var actors []Actor
+	err := d.get("actors", strconv.Itoa(seriesID), &actors)
+	return actors, err
+}
+
+// PutActors implements the Store interface.
+func (d *DirStore) PutActors(seriesID int, actors []Actor) error {
+	return d.put("actors", strconv.Itoa(seriesID), actors)
+}
+
+// DeleteActors implements the Store interface.
+func (d *DirStore) DeleteActors(seriesID int) error {
+	return d.delete("actors", strconv.Itoa(seriesID))
+}
+
+// GetImages implements the Store interface.
+func (d *DirStore) GetImages(seriesID int) ([]Image, error) {
+	var images []Image
+	err := d.get("images", strconv.Itoa(seriesID), &images)
+	return images, err
+}
+
+// PutImages implements the Store interface.
+func (d *DirStore) PutImages(seriesID int, images []Image) error {
+	return d.put("images", strconv.Itoa(seriesID), images)
+}
+
+// DeleteImages implements the Store interface.
+func (d *DirStore) DeleteImages(seriesID int) error {
+	return d.delete("images", strconv.Itoa(seriesID))
+}
+
+// GetCheckpoint implements the Store interface.
+func (d *DirStore) GetCheckpoint(name string) (Checkpoint, error) {
+	var c Checkpoint
+	err := d.get("checkpoints", name, &c)
+	return c, err
+}
+
+// PutCheckpoint implements the Store interface.
+func (d *DirStore) PutCheckpoint(name string, c Checkpoint) error {
+	return d.put("checkpoints", name, c)
+}
+
+// DeleteCheckpoint implements the Store interface.
+func (d *DirStore) DeleteCheckpoint(name string) error {
+	return d.delete("checkpoints", name)
+}
+
+func (d *DirStore) get(kind, name string, v interface{}) error {
+	data, err := os.ReadFile(d.path(kind, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotStored
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (d *DirStore) put(kind, name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(d.path(kind, name), data)
+}
+
+func (d *DirStore) delete(kind, name string) error {
+	err := os.Remove(d.path(kind, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (d *DirStore) path(kind, name string) string {
+	return filepath.Join(d.dir, kind, url.PathEscape(name)+".json")
+}
+
+// version returns the layout version of the directory. A directory without
+// the version file is a new store.
+func (d *DirStore) version() (int, error) {
+	data, err := os.ReadFile(filepath.Join(d.dir, "version"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return dirStoreVersion, d.setVersion(dirStoreVersion)
+	}
+	if err != nil {
+		return 0, err
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid store version %q", strings.TrimSpace(string(data)))
+	}
+	return version, nil
+}
+
+func (d *DirStore) setVersion(version int) error {
+	return writeFileAtomic(filepath.Join(d.dir, "version"), []byte(strconv.Itoa(version)+"\n"))
+}
@@ -0,0 +1,189 @@
+package tvdb
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrCacheMiss is returned by the client methods when the cache is offline
+// and the response of a request is not cached.
+var ErrCacheMiss = errors.New("response not cached")
+
+// OfflineCache is a Cache that can serve the requests without network. If
+// Client.Cache is an OfflineCache and Offline returns true the client never
+// performs http requests: responses are served only from the cache, also if
+// expired, and missing ones return ErrCacheMiss.
+type OfflineCache interface {
+	Cache
+	Offline() bool
+}
+
+// DiskCache is a Cache that stores every response in a json file in a
+// directory, so that the responses survive the process and can be committed
+// to be used in offline mode, for example to run tests without network.
+//
+// The file of a response is named after the request, like
+// dir/en/series/121361/episodes/query/page=1.json, and contains the raw json
+// response and its expiration time. Files can be created with Seed.
+type DiskCache struct {
+	dir     string
+	offline bool
+}
+
+type diskEntry struct {
+	Key    string    `json:"key"`
+	Stored time.Time `json:"stored"`
+	// Zero if the entry never expires.
+	Expires time.Time       `json:"expires"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// NewDiskCache returns a DiskCache that stores the responses in dir. If
+// offline is true the client will serve the requests only from the cache.
+func NewDiskCache(dir string, offline bool) *DiskCache {
+	return &DiskCache{dir: dir, offline: offline}
+}
+
+// Offline implements the OfflineCache interface.
+func (d *DiskCache) Offline() bool {
+	return d.offline
+}
+
+// Get implements the Cache interface. In offline mode expired responses are
+// returned too.
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry diskEntry
+	if json.Unmarshal(data, &entry) != nil || entry.Key != key {
+		return nil, false
+	}
+	if !d.offline && !entry.Expires.IsZero() && time.Now().After(entry.Expires) {
+		return nil, false
+	}
+	return entry.Data, true
+}
+
+// Set implements the Cache interface. Errors writing the file are ignored,
+// the response will simply not be cached.
+func (d *DiskCache) Set(key string, data []byte, ttl time.Duration) {
+	d.write(key, data, time.Now().Add(ttl))
+}
+
+// Delete implements the Cache interface.
+func (d *DiskCache) Delete(key string) {
+	os.Remove(d.path(key))
+}
+
+// EvictPrefix implements the EvictableCache interface. Seeded entries are
+// never evicted.
+func (d *DiskCache) EvictPrefix(prefix string, before time.Time) []string {
+	keys := make([]string, 0)
+	languages, err := os.ReadDir(d.dir)
+	if err != nil {
+		return keys
+	}
+	// Only the directory of the complete api path segments of the prefix
+	// needs to be walked.
+	path := prefix[:strings.LastIndex(prefix, "/")+1]
+	if i := strings.IndexAny(prefix, "?#"); i >= 0 {
+		path = prefix[:i]
+	}
+	for _, language := range languages {
+		if !language.IsDir() {
+			continue
+		}
+		filepath.WalkDir(d.dirPath(language.Name(), path), func(file string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || filepath.Ext(file) != ".json" {
+				return nil
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil
+			}
+			var e diskEntry
+			if json.Unmarshal(data, &e) != nil || e.Expires.IsZero() {
+				return nil
+			}
+			if strings.HasPrefix(e.Key, prefix) && e.Stored.Before(before) && os.Remove(file) == nil {
+				keys = append(keys, e.Key)
+			}
+			return nil
+		})
+	}
+	return keys
+}
+
+// Seed stores the raw json response data with key without expiration. Use
+// CacheKey to build the key of a request.
+func (d *DiskCache) Seed(key string, data []byte) error {
+	return d.write(key, data, time.Time{})
+}
+
+func (d *DiskCache) write(key string, data []byte, expires time.Time) error {
+	if !json.Valid(data) {
+		return errors.New("invalid json data")
+	}
+	content, err := json.Marshal(diskEntry{Key: key, Stored: time.Now(), Expires: expires, Data: data})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(d.path(key), content)
+}
+
+// writeFileAtomic writes data to the file path, creating its directory. The
+// data is written to a temporary file that is renamed to path, so that a
+// concurrent reader never reads a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// path returns the file path of the key, made of the language, the api
+// endpoint path and the escaped query.
+func (d *DiskCache) path(key string) string {
+	rest, language, _ := strings.Cut(key, "#")
+	path, query, _ := strings.Cut(rest, "?")
+	if language == "" {
+		language = "_"
+	}
+	name := "_"
+	if query != "" {
+		name = url.PathEscape(query)
+	}
+	return filepath.Join(d.dirPath(language, path), name+".json")
+}
+
+// dirPath returns the directory of the responses of an api endpoint path.
+func (d *DiskCache) dirPath(language, path string) string {
+	parts := []string{d.dir, url.PathEscape(language)}
+	for _, p := range strings.Split(strings.Trim(path, "/"), "/") {
+		if p != "" && p != "." && p != ".." {
+			parts = append(parts, url.PathEscape(p))
+		}
+	}
+	return filepath.Join(parts...)
+}
@@ -1,5 +1,7 @@
 package tvdb
 
+import "time"
+
 // Episode struct store all data of an episode.
 type Episode struct {
 	AbsoluteNumber     int      `json:"absoluteNumber"`
@@ -40,3 +42,21 @@ type Episode struct {
 func (e *Episode) Empty() bool {
 	return e.ID == 0 && e.EpisodeName == ""
 }
+
+// ThumbnailURL returns the complete URL of the episode thumbnail image.
+func (e *Episode) ThumbnailURL() string {
+	if e.Filename == "" {
+		return ""
+	}
+	return ImageURL(e.Filename)
+}
+
+// parseAirDate parses a date in the format used by the TVDB api for aired
+// dates (2006-01-02).
+func parseAirDate(date string) (time.Time, bool) {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,144 @@
+package tvdb
+
+import "sync"
+
+// indexMu guards the index field of all the series. The index is built on the
+// first lookup, so without the lock concurrent lookups on a shared series
+// would race. A package lock is used because series are copied by value.
+var indexMu sync.Mutex
+
+// episodeIndex maps the episode identifiers to positions in series.Episodes.
+// The index remembers the slice it was built from, so it is rebuilt whenever
+// series.Episodes is replaced or its length changes. An index is never
+// modified after it has been built.
+type episodeIndex struct {
+	first    *Episode
+	length   int
+	byID     map[int]int
+	byAired  map[[2]int]int
+	bySeason map[int][]int
+	byDvd    map[dvdNumber]int
+	byAbs    map[int]int
+	byImdb   map[string]int
+	byDate   map[string][]int
+}
+
+type dvdNumber struct {
+	season int
+	number float64
+}
+
+func newEpisodeIndex(episodes []Episode) *episodeIndex {
+	idx := &episodeIndex{
+		length:   len(episodes),
+		byID:     make(map[int]int),
+		byAired:  make(map[[2]int]int),
+		bySeason: make(map[int][]int),
+		byDvd:    make(map[dvdNumber]int),
+		byAbs:    make(map[int]int),
+		byImdb:   make(map[string]int),
+		byDate:   make(map[string][]int),
+	}
+	if len(episodes) > 0 {
+		idx.first = &episodes[0]
+	}
+	for i := range episodes {
+		ep := &episodes[i]
+		idx.bySeason[ep.AiredSeason] = append(idx.bySeason[ep.AiredSeason], i)
+		if ep.FirstAired != "" {
+			idx.byDate[ep.FirstAired] = append(idx.byDate[ep.FirstAired], i)
+		}
+	}
+	// Iterate backwards so that, with duplicated keys, the first episode wins
+	// like in a linear scan.
+	for i := len(episodes) - 1; i >= 0; i-- {
+		ep := &episodes[i]
+		if ep.ID != 0 {
+			idx.byID[ep.ID] = i
+		}
+		idx.byAired[[2]int{ep.AiredSeason, ep.AiredEpisodeNumber}] = i
+		if ep.DvdEpisodeNumber != 0 {
+			idx.byDvd[dvdNumber{ep.DvdSeason, ep.DvdEpisodeNumber}] = i
+		}
+		if ep.AbsoluteNumber != 0 {
+			idx.byAbs[ep.AbsoluteNumber] = i
+		}
+		if ep.ImdbID != "" {
+			idx.byImdb[ep.ImdbID] = i
+		}
+	}
+	return idx
+}
+
+func (idx *episodeIndex) valid(episodes []Episode) bool {
+	if idx == nil || idx.length != len(episodes) {
+		return false
+	}
+	return len(episodes) == 0 || idx.first == &episodes[0]
+}
+
+// episodeIndex returns the index of the series episodes, building it if
+// missing or stale.
+func (s *Series) episodeIndex() *episodeIndex {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	if !s.index.valid(s.Episodes) {
+		s.index = newEpisodeIndex(s.Episodes)
+	}
+	return s.index
+}
+
+// resetEpisodeIndex drops the index, to be called when the episodes are
+// replaced.
+func (s *Series) resetEpisodeIndex() {
+	indexMu.Lock()
+	s.index = nil
+	indexMu.Unlock()
+}
+
+// rebuildEpisodeIndex builds the index again, to be called when a lookup
+// finds that the episodes changed in place.
+func (s *Series) rebuildEpisodeIndex() *episodeIndex {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	s.index = newEpisodeIndex(s.Episodes)
+	return s.index
+}
+
+// lookupEpisode returns the first episode that matches, using the position
+// returned by pos from the index. The index can't see the episodes sorted or
+// edited in place, so the indexed episode is verified with match and, if it
+// doesn't match or the key is not indexed, the episodes are scanned. If the
+// scan finds the episode the index is rebuilt.
+func (s *Series) lookupEpisode(pos func(idx *episodeIndex) (int, bool), match func(ep *Episode) bool) *Episode {
+	if i, ok := pos(s.episodeIndex()); ok && i < len(s.Episodes) && match(&s.Episodes[i]) {
+		return &s.Episodes[i]
+	}
+	for i := range s.Episodes {
+		if match(&s.Episodes[i]) {
+			s.rebuildEpisodeIndex()
+			return &s.Episodes[i]
+		}
+	}
+	return nil
+}
+
+// lookupEpisodes returns the episodes at the positions returned by pos from
+// the index. If one of them doesn't match anymore, because the episodes were
+// sorted or edited in place, the index is rebuilt and the positions are taken
+// from the new index. An episode edited in place to match, like an episode
+// moved to another season, is seen only once the index is rebuilt.
+func (s *Series) lookupEpisodes(pos func(idx *episodeIndex) []int, match func(ep *Episode) bool) []*Episode {
+	positions := pos(s.episodeIndex())
+	for _, i := range positions {
+		if i >= len(s.Episodes) || !match(&s.Episodes[i]) {
+			positions = pos(s.rebuildEpisodeIndex())
+			break
+		}
+	}
+	episodes := make([]*Episode, 0, len(positions))
+	for _, i := range positions {
+		episodes = append(episodes, &s.Episodes[i])
+	}
+	return episodes
+}
@@ -1,6 +1,7 @@
 package tvdb_test
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -39,8 +40,7 @@ func ExampleClient_BestSearch() {
 	}
 	series, err := c.BestSearch("Game of Thrones")
 	if err != nil {
-		// The request response is a 404: this means no results have been found
-		if tvdb.HaveCodeError(404, err) {
+		if errors.Is(err, tvdb.ErrNoResults) {
 			fmt.Println("Series not found")
 		} else {
 			panic(err)
@@ -0,0 +1,142 @@
+// Package ical renders the episodes of TVDB series as an iCalendar (RFC 5545)
+// feed, so that users can subscribe to the air dates of their shows with a
+// calendar application.
+//
+// Every episode with a first aired date becomes a VEVENT. If the series airs
+// time is known the event starts at that time and lasts the series runtime,
+// otherwise it is an all-day event. Event UIDs are derived from the episode
+// ids so calendar applications update events instead of duplicating them when
+// the feed is refreshed.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pioz/tvdb"
+)
+
+// ContentType is the MIME type of an iCalendar feed.
+const ContentType = "text/calendar; charset=utf-8"
+
+// Options holds the optional settings of the calendar.
+type Options struct {
+	// Name of the calendar (X-WR-CALNAME).
+	Name string
+	// Location in which the series airs times are interpreted. If nil UTC is
+	// used.
+	Location *time.Location
+	// Domain used in the event UIDs (default thetvdb.com).
+	Domain string
+	// Timestamp of the events (DTSTAMP). If zero the current time is used.
+	Now time.Time
+}
+
+// Encode writes to w an iCalendar with an event for every episode of the
+// series. Episodes are read from the series Episodes field, so they must be
+// retrieved before with Client.GetSeriesEpisodes.
+func Encode(w io.Writer, series []tvdb.Series, opts Options) error {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.Domain == "" {
+		opts.Domain = "thetvdb.com"
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	e := &encoder{w: bufio.NewWriter(w)}
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:-//pioz//tvdb//EN")
+	e.line("CALSCALE:GREGORIAN")
+	if opts.Name != "" {
+		e.line("X-WR-CALNAME:" + escape(opts.Name))
+	}
+	for i := range series {
+		s := &series[i]
+		for j := range s.Episodes {
+			e.event(s, &s.Episodes[j], opts)
+		}
+	}
+	e.line("END:VCALENDAR")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) event(s *tvdb.Series, ep *tvdb.Episode, opts Options) {
+	start, ok := s.AirTime(ep, opts.Location)
+	if !ok {
+		return
+	}
+	e.line("BEGIN:VEVENT")
+	e.line(fmt.Sprintf("UID:episode-%d@%s", ep.ID, opts.Domain))
+	e.line("DTSTAMP:" + opts.Now.UTC().Format("20060102T150405Z"))
+	if _, _, timed := tvdb.ParseAirsTime(s.AirsTime); timed {
+		runtime, err := strconv.Atoi(strings.TrimSpace(s.Runtime))
+		if err != nil || runtime <= 0 {
+			runtime = 30
+		}
+		e.line("DTSTART:" + start.UTC().Format("20060102T150405Z"))
+		e.line("DTEND:" + start.Add(time.Duration(runtime)*time.Minute).UTC().Format("20060102T150405Z"))
+	} else {
+		e.line("DTSTART;VALUE=DATE:" + start.Format("20060102"))
+		e.line("DTEND;VALUE=DATE:" + start.AddDate(0, 0, 1).Format("20060102"))
+	}
+	summary := fmt.Sprintf("%s - S%02dE%02d", s.SeriesName, ep.AiredSeason, ep.AiredEpisodeNumber)
+	if ep.EpisodeName != "" {
+		summary += " - " + ep.EpisodeName
+	}
+	e.line("SUMMARY:" + escape(summary))
+	if ep.Overview != "" {
+		e.line("DESCRIPTION:" + escape(ep.Overview))
+	}
+	if s.Network != "" {
+		e.line("LOCATION:" + escape(s.Network))
+	}
+	e.line("END:VEVENT")
+}
+
+// line writes a content line folded at 75 octets as required by RFC 5545,
+// without splitting UTF-8 sequences.
+func (e *encoder) line(l string) {
+	if e.err != nil {
+		return
+	}
+	limit := 75
+	for len(l) > limit {
+		cut := limit
+		for cut > 0 && !utf8Start(l[cut]) {
+			cut--
+		}
+		if _, e.err = e.w.WriteString(l[:cut] + "\r\n "); e.err != nil {
+			return
+		}
+		l = l[cut:]
+		// Continuation lines start with a space that counts in the limit.
+		limit = 74
+	}
+	_, e.err = e.w.WriteString(l + "\r\n")
+}
+
+func utf8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return textEscaper.Replace(s)
+}
@@ -0,0 +1,42 @@
+package ical_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pioz/tvdb"
+	"github.com/pioz/tvdb/ical"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	series := []tvdb.Series{
+		{ID: 1, SeriesName: "Show", AirsTime: "9:00 PM", Runtime: "60", Network: "HBO", Episodes: []tvdb.Episode{
+			{ID: 10, AiredSeason: 1, AiredEpisodeNumber: 2, EpisodeName: "One, Two", FirstAired: "2024-03-05", Overview: strings.Repeat("long overview; ", 10)},
+			{ID: 11, AiredSeason: 1, AiredEpisodeNumber: 3},
+		}},
+		{ID: 2, SeriesName: "Daily", Episodes: []tvdb.Episode{
+			{ID: 20, AiredSeason: 2024, AiredEpisodeNumber: 1, FirstAired: "2024-03-06"},
+		}},
+	}
+	var buf bytes.Buffer
+	err := ical.Encode(&buf, series, ical.Options{Name: "My shows", Now: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	assert.Equal(t, 2, strings.Count(out, "BEGIN:VEVENT"))
+	assert.Contains(t, out, "UID:episode-10@thetvdb.com\r\n")
+	assert.Contains(t, out, "DTSTART:20240305T210000Z\r\nDTEND:20240305T220000Z\r\n")
+	assert.Contains(t, out, "SUMMARY:Show - S01E02 - One\\, Two\r\n")
+	assert.Contains(t, out, "LOCATION:HBO\r\n")
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20240306\r\nDTEND;VALUE=DATE:20240307\r\n")
+	assert.Contains(t, out, "DTSTAMP:20240301T000000Z\r\n")
+	for _, line := range strings.Split(out, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+}
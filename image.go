@@ -1,5 +1,11 @@
 package tvdb
 
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
 // Image struct store all data of an image.
 type Image struct {
 	FileName    string `json:"fileName"`
@@ -12,7 +18,7 @@ type Image struct {
 	RatingsInfo Rating `json:"ratingsInfo"`
 }
 
-// holds image ratings
+// Rating holds image ratings.
 type Rating struct {
 	Average float64 `json:"average"`
 	Count   int     `json:"count"`
@@ -25,3 +31,115 @@ type Rating struct {
 func ImageURL(fileName string) string {
 	return "https://thetvdb.com/banners/" + fileName
 }
+
+// URL returns the complete URL of the image.
+func (i *Image) URL() string {
+	if i.FileName == "" {
+		return ""
+	}
+	return ImageURL(i.FileName)
+}
+
+// ThumbnailURL returns the complete URL of the image thumbnail.
+func (i *Image) ThumbnailURL() string {
+	if i.Thumbnail == "" {
+		return ""
+	}
+	return ImageURL(i.Thumbnail)
+}
+
+// Size returns the width and height of the image parsed from the resolution
+// field (for example 1920x1080). The last value is false if the resolution is
+// missing or malformed.
+func (i *Image) Size() (int, int, bool) {
+	w, h, found := strings.Cut(strings.ToLower(i.Resolution), "x")
+	if !found {
+		return 0, 0, false
+	}
+	width, err := strconv.Atoi(strings.TrimSpace(w))
+	if err != nil {
+		return 0, 0, false
+	}
+	height, err := strconv.Atoi(strings.TrimSpace(h))
+	if err != nil {
+		return 0, 0, false
+	}
+	return width, height, true
+}
+
+// ImageSelection holds the criteria used by BestImage to pick an image.
+type ImageSelection struct {
+	// Language ids in order of preference. Images in other languages are
+	// selected only if there are no images in the preferred languages.
+	Languages []int
+	// Minimum width and height of the image. If set, images with unknown
+	// resolution are discarded.
+	MinWidth  int
+	MinHeight int
+	// Number of votes of the prior used to weight the rating average by the
+	// votes count (default 5). With few votes the rating is pulled towards
+	// the mean rating of the candidate images.
+	RatingPrior int
+}
+
+// BestImage selects the best image according to the criteria in sel: the
+// language preference first, then the rating average weighted by the votes
+// count and finally the resolution. The boolean is false if no image matches.
+func BestImage(images []Image, sel ImageSelection) (Image, bool) {
+	candidates := make([]Image, 0, len(images))
+	for _, image := range images {
+		if sel.MinWidth > 0 || sel.MinHeight > 0 {
+			w, h, ok := image.Size()
+			if !ok || w < sel.MinWidth || h < sel.MinHeight {
+				continue
+			}
+		}
+		candidates = append(candidates, image)
+	}
+	if len(candidates) == 0 {
+		return Image{}, false
+	}
+
+	prior := float64(sel.RatingPrior)
+	if sel.RatingPrior <= 0 {
+		prior = 5
+	}
+	var sum float64
+	var votes int
+	for _, image := range candidates {
+		sum += image.RatingsInfo.Average * float64(image.RatingsInfo.Count)
+		votes += image.RatingsInfo.Count
+	}
+	mean := 0.0
+	if votes > 0 {
+		mean = sum / float64(votes)
+	}
+	score := func(image Image) float64 {
+		count := float64(image.RatingsInfo.Count)
+		return (image.RatingsInfo.Average*count + mean*prior) / (count + prior)
+	}
+	languageRank := func(image Image) int {
+		for rank, id := range sel.Languages {
+			if image.LanguageID == id {
+				return rank
+			}
+		}
+		return len(sel.Languages)
+	}
+	area := func(image Image) int {
+		w, h, _ := image.Size()
+		return w * h
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if ra, rb := languageRank(a), languageRank(b); ra != rb {
+			return ra < rb
+		}
+		if sa, sb := score(a), score(b); sa != sb {
+			return sa > sb
+		}
+		return area(a) > area(b)
+	})
+	return candidates[0], true
+}
@@ -0,0 +1,42 @@
+package tvdb_test
+
+import (
+	"testing"
+
+	"github.com/pioz/tvdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageHelpers(t *testing.T) {
+	image := tvdb.Image{FileName: "posters/1.jpg", Thumbnail: "_cache/posters/1.jpg", Resolution: "680x1000"}
+	assert.Equal(t, "https://thetvdb.com/banners/posters/1.jpg", image.URL())
+	assert.Equal(t, "https://thetvdb.com/banners/_cache/posters/1.jpg", image.ThumbnailURL())
+	w, h, ok := image.Size()
+	assert.True(t, ok)
+	assert.Equal(t, 680, w)
+	assert.Equal(t, 1000, h)
+	_, _, ok = (&tvdb.Image{}).Size()
+	assert.False(t, ok)
+	actor := tvdb.Actor{Image: "actors/1.jpg"}
+	assert.Equal(t, "https://thetvdb.com/banners/actors/1.jpg", actor.ImageURL())
+	assert.Equal(t, "", (&tvdb.Episode{}).ThumbnailURL())
+}
+
+func TestBestImage(t *testing.T) {
+	images := []tvdb.Image{
+		{ID: 1, LanguageID: 7, Resolution: "680x1000", RatingsInfo: tvdb.Rating{Average: 10, Count: 1}},
+		{ID: 2, LanguageID: 7, Resolution: "680x1000", RatingsInfo: tvdb.Rating{Average: 8, Count: 50}},
+		{ID: 3, LanguageID: 14, Resolution: "680x1000", RatingsInfo: tvdb.Rating{Average: 9, Count: 10}},
+		{ID: 4, LanguageID: 7, Resolution: "340x500", RatingsInfo: tvdb.Rating{Average: 10, Count: 100}},
+		{ID: 5, LanguageID: 7, Resolution: "680x1000", RatingsInfo: tvdb.Rating{Average: 2, Count: 40}},
+	}
+	best, ok := tvdb.BestImage(images, tvdb.ImageSelection{Languages: []int{7}, MinWidth: 600})
+	assert.True(t, ok)
+	assert.Equal(t, 2, best.ID)
+	best, _ = tvdb.BestImage(images, tvdb.ImageSelection{Languages: []int{14, 7}})
+	assert.Equal(t, 3, best.ID)
+	best, _ = tvdb.BestImage(images, tvdb.ImageSelection{})
+	assert.Equal(t, 4, best.ID)
+	_, ok = tvdb.BestImage(images, tvdb.ImageSelection{MinWidth: 2000})
+	assert.False(t, ok)
+}
@@ -0,0 +1,120 @@
+package tvdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Invalidator keeps the client cache fresh polling the updates of the TVDB
+// api: the cached responses of a series (the series record, episodes,
+// summary, actors and images) stored before the series was last updated are
+// evicted and, if Refresh is true, retrieved again. This allows to use long
+// cache TTLs while picking up the edits within minutes.
+//
+// The responses of GetEpisode are not related to a series in the updates
+// feed, so they only expire with their TTL.
+type Invalidator struct {
+	Client *Client
+	// Time between two polls (default 5 minutes).
+	Interval time.Duration
+	// If Refresh is true the evicted responses are retrieved again.
+	Refresh bool
+	// Epoch time of the last poll. The next poll asks the updates since this
+	// time. If zero the updates of the last 24 hours are polled.
+	Since int64
+	// OnEvict, if not nil, is called for every updated series with the
+	// evicted cache keys.
+	OnEvict func(u Update, keys []string)
+}
+
+// ErrCacheNotEvictable is returned by the Invalidator when the client cache
+// doesn't implement EvictableCache.
+var ErrCacheNotEvictable = errors.New("the client cache is not evictable")
+
+// NewInvalidator returns an Invalidator of the cache of the client c that
+// polls the updates every interval.
+func NewInvalidator(c *Client, interval time.Duration) *Invalidator {
+	return &Invalidator{Client: c, Interval: interval}
+}
+
+// Poll retrieves the updates since the last poll and evicts the cached
+// responses of the updated series. Returns the updates.
+func (inv *Invalidator) Poll() ([]Update, error) {
+	cache, ok := inv.Client.Cache.(EvictableCache)
+	if !ok {
+		return nil, ErrCacheNotEvictable
+	}
+	now := time.Now()
+	since := inv.Since
+	if since == 0 {
+		since = now.Add(-24 * time.Hour).Unix()
+	}
+	updates, err := inv.Client.GetUpdates(int(since))
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, u := range updates {
+		updated := time.Unix(int64(u.LastUpdated), 0)
+		keys := cache.EvictPrefix(fmt.Sprintf("/series/%d?", u.ID), updated)
+		keys = append(keys, cache.EvictPrefix(fmt.Sprintf("/series/%d/", u.ID), updated)...)
+		if len(keys) == 0 {
+			continue
+		}
+		if inv.OnEvict != nil {
+			inv.OnEvict(u, keys)
+		}
+		if inv.Refresh {
+			for _, key := range keys {
+				if err := inv.Client.refresh(key); err != nil {
+					errs = append(errs, fmt.Errorf("refresh %s: %w", key, err))
+				}
+			}
+		}
+	}
+	// Overlap the polls a little to not miss the updates made during this
+	// poll.
+	inv.Since = now.Add(-time.Minute).Unix()
+	return updates, errors.Join(errs...)
+}
+
+// Run polls the updates every Interval until ctx is done. Errors are not
+// fatal: the poll is retried at the next interval. Returns the context error.
+func (inv *Invalidator) Run(ctx context.Context) error {
+	interval := inv.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		inv.Poll()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh performs again the GET request of a cache key, storing the new
+// response in the cache.
+func (c *Client) refresh(key string) error {
+	rest, language, _ := strings.Cut(key, "#")
+	path, query, _ := strings.Cut(rest, "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return err
+	}
+	client := *c
+	client.Language = language
+	resp, err := client.performGETRequest(path, params)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
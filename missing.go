@@ -0,0 +1,171 @@
+package tvdb
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pioz/tvdb/parse"
+)
+
+// Ordering is the numbering used to match local episodes with the series
+// episodes.
+type Ordering int
+
+const (
+	// AiredOrder uses the aired season and episode numbers.
+	AiredOrder Ordering = iota
+	// DvdOrder uses the DVD season and episode numbers. Split episodes
+	// numbered 1.1, 1.2 on DVD all match the local episode number 1.
+	DvdOrder
+	// AbsoluteOrder uses the absolute numbers, the season is ignored.
+	AbsoluteOrder
+)
+
+// EpisodeNumber identifies a local episode by season and episode number.
+type EpisodeNumber struct {
+	Season  int
+	Episode int
+}
+
+// MissingOptions holds the options of the missing episodes report.
+type MissingOptions struct {
+	Ordering Ordering
+	// If IgnoreSpecials is true the episodes of season 0 are not reported.
+	IgnoreSpecials bool
+	// Episodes first aired after Now are unaired. If zero the current time
+	// is used.
+	Now time.Time
+}
+
+// MissingReport is the comparison between the series episodes and a local
+// library.
+type MissingReport struct {
+	// Aired episodes not present in the library.
+	Missing []*Episode
+	// Episodes not present in the library that are not aired yet or have no
+	// first aired date.
+	Unaired []*Episode
+	// Local episodes that don't correspond to any series episode.
+	Unknown []EpisodeNumber
+	// Files of the library that can't be parsed or don't correspond to any
+	// series episode, filled only by MissingEpisodesInDir.
+	UnknownFiles []string
+}
+
+// MissingEpisodes compares the series episodes with the local episodes owned
+// and reports the missing, unaired and unknown episodes. Episodes must be
+// retrieved before with GetSeriesEpisodes.
+func (s *Series) MissingEpisodes(owned []EpisodeNumber, opts MissingOptions) MissingReport {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	have := make(map[EpisodeNumber]bool, len(owned))
+	for _, n := range owned {
+		if opts.Ordering == AbsoluteOrder {
+			n.Season = 0
+		}
+		have[n] = true
+	}
+	var report MissingReport
+	known := make(map[EpisodeNumber]bool, len(s.Episodes))
+	for _, ep := range s.ChronologicalEpisodes() {
+		n, ok := ep.number(opts.Ordering)
+		if !ok {
+			continue
+		}
+		known[n] = true
+		if have[n] || (opts.IgnoreSpecials && ep.AiredSeason == 0) {
+			continue
+		}
+		aired, ok := parseAirDate(ep.FirstAired)
+		if ok && !aired.After(opts.Now) {
+			report.Missing = append(report.Missing, ep)
+		} else {
+			report.Unaired = append(report.Unaired, ep)
+		}
+	}
+	for n := range have {
+		if !known[n] {
+			report.Unknown = append(report.Unknown, n)
+		}
+	}
+	sort.Slice(report.Unknown, func(i, j int) bool {
+		a, b := report.Unknown[i], report.Unknown[j]
+		return a.Season < b.Season || (a.Season == b.Season && a.Episode < b.Episode)
+	})
+	return report
+}
+
+// MissingEpisodesInDir is like MissingEpisodes but the local episodes are
+// read from the names of the video files in dir and its subdirectories, see
+// the parse package. Files that can't be parsed or don't correspond to any
+// series episode are reported in UnknownFiles.
+func (s *Series) MissingEpisodesInDir(dir string, opts MissingOptions) (MissingReport, error) {
+	owned := make([]EpisodeNumber, 0)
+	files := make(map[EpisodeNumber][]string)
+	unparsed := make([]string, 0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !parse.IsVideo(path) {
+			return nil
+		}
+		r, err := parse.File(path)
+		if err != nil {
+			unparsed = append(unparsed, path)
+			return nil
+		}
+		numbers := make([]EpisodeNumber, 0)
+		if opts.Ordering == AbsoluteOrder && len(r.Absolute) > 0 {
+			for _, n := range r.Absolute {
+				numbers = append(numbers, EpisodeNumber{0, n})
+			}
+		} else {
+			for _, n := range r.Episodes {
+				numbers = append(numbers, EpisodeNumber{r.Season, n})
+			}
+		}
+		if len(numbers) == 0 {
+			unparsed = append(unparsed, path)
+		}
+		for _, n := range numbers {
+			owned = append(owned, n)
+			files[n] = append(files[n], path)
+		}
+		return nil
+	})
+	if err != nil {
+		return MissingReport{}, err
+	}
+	report := s.MissingEpisodes(owned, opts)
+	report.UnknownFiles = unparsed
+	seen := make(map[string]bool)
+	for _, n := range report.Unknown {
+		for _, path := range files[n] {
+			if !seen[path] {
+				seen[path] = true
+				report.UnknownFiles = append(report.UnknownFiles, path)
+			}
+		}
+	}
+	sort.Strings(report.UnknownFiles)
+	return report, nil
+}
+
+// number returns the number of the episode in the ordering. The boolean is
+// false if the episode has no number in that ordering.
+func (e *Episode) number(o Ordering) (EpisodeNumber, bool) {
+	switch o {
+	case DvdOrder:
+		if e.DvdEpisodeNumber == 0 {
+			return EpisodeNumber{}, false
+		}
+		return EpisodeNumber{e.DvdSeason, int(e.DvdEpisodeNumber)}, true
+	case AbsoluteOrder:
+		return EpisodeNumber{0, e.AbsoluteNumber}, e.AbsoluteNumber > 0
+	}
+	return EpisodeNumber{e.AiredSeason, e.AiredEpisodeNumber}, true
+}
@@ -0,0 +1,63 @@
+package tvdb_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pioz/tvdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func missingSeries() tvdb.Series {
+	return tvdb.Series{ID: 1, SeriesName: "Show", Episodes: []tvdb.Episode{
+		{ID: 1, AiredSeason: 1, AiredEpisodeNumber: 1, AbsoluteNumber: 1, DvdSeason: 1, DvdEpisodeNumber: 1.1, FirstAired: "2020-01-01"},
+		{ID: 2, AiredSeason: 1, AiredEpisodeNumber: 2, AbsoluteNumber: 2, DvdSeason: 1, DvdEpisodeNumber: 1.2, FirstAired: "2020-01-08"},
+		{ID: 3, AiredSeason: 1, AiredEpisodeNumber: 3, AbsoluteNumber: 3, DvdSeason: 1, DvdEpisodeNumber: 2, FirstAired: "2020-01-15"},
+		{ID: 4, AiredSeason: 0, AiredEpisodeNumber: 1, FirstAired: "2020-01-20"},
+		{ID: 5, AiredSeason: 2, AiredEpisodeNumber: 1, AbsoluteNumber: 4, FirstAired: "2030-01-01"},
+		{ID: 6, AiredSeason: 2, AiredEpisodeNumber: 2, AbsoluteNumber: 5},
+	}}
+}
+
+func episodeIDs(episodes []*tvdb.Episode) []int {
+	ids := make([]int, 0)
+	for _, ep := range episodes {
+		ids = append(ids, ep.ID)
+	}
+	return ids
+}
+
+func TestSeriesMissingEpisodes(t *testing.T) {
+	s := missingSeries()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	report := s.MissingEpisodes([]tvdb.EpisodeNumber{{1, 1}, {1, 3}, {3, 1}}, tvdb.MissingOptions{Now: now})
+	assert.Equal(t, []int{2, 4}, episodeIDs(report.Missing))
+	assert.Equal(t, []int{5, 6}, episodeIDs(report.Unaired))
+	assert.Equal(t, []tvdb.EpisodeNumber{{3, 1}}, report.Unknown)
+
+	report = s.MissingEpisodes([]tvdb.EpisodeNumber{{1, 1}}, tvdb.MissingOptions{Now: now, Ordering: tvdb.DvdOrder, IgnoreSpecials: true})
+	assert.Equal(t, []int{3}, episodeIDs(report.Missing))
+	assert.Empty(t, report.Unaired)
+
+	report = s.MissingEpisodes([]tvdb.EpisodeNumber{{0, 2}, {1, 3}}, tvdb.MissingOptions{Now: now, Ordering: tvdb.AbsoluteOrder})
+	assert.Equal(t, []int{1}, episodeIDs(report.Missing))
+	assert.Equal(t, []int{4, 5}, []int{report.Unaired[0].AbsoluteNumber, report.Unaired[1].AbsoluteNumber})
+}
+
+func TestSeriesMissingEpisodesInDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"Show.S01E01E02.mkv", "Show.S05E01.mkv", "holidays.mkv", "Show.S01E03.srt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := missingSeries()
+	report, err := s.MissingEpisodesInDir(dir, tvdb.MissingOptions{Now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), IgnoreSpecials: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []int{3}, episodeIDs(report.Missing))
+	assert.Equal(t, []string{filepath.Join(dir, "Show.S05E01.mkv"), filepath.Join(dir, "holidays.mkv")}, report.UnknownFiles)
+}
@@ -0,0 +1,122 @@
+package tvdb
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var partSuffixRegexp = regexp.MustCompile(`(?i)\s*(?:[,:\-]\s*)?(?:\(\s*(?:part\s+|pt\.?\s*)?([0-9]{1,2}|[ivx]+)\s*\)|\b(?:part|pt\.?)\s+([0-9]+|[ivx]+|one|two|three|four|five))\s*$`)
+
+var partWords = map[string]int{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"i": 1, "ii": 2, "iii": 3, "iv": 4, "v": 5, "vi": 6, "vii": 7, "viii": 8, "ix": 9, "x": 10,
+}
+
+// BaseName returns the episode name without the part suffix, so for example
+// both "The Trial (1)" and "The Trial, Part 2" become "The Trial".
+func (e *Episode) BaseName() string {
+	return strings.TrimSpace(partSuffixRegexp.ReplaceAllString(e.EpisodeName, ""))
+}
+
+// PartNumber returns the part number of a multi-part episode. The part is
+// taken from the fractional DVD episode number (1.1, 1.2, ...) or from a name
+// suffix like "(2)" or "Part 2". Returns 0 if the episode doesn't look like a
+// part of a multi-part episode.
+func (e *Episode) PartNumber() int {
+	if _, frac := math.Modf(e.DvdEpisodeNumber); frac > 0 {
+		return int(math.Round(frac * 10))
+	}
+	m := partSuffixRegexp.FindStringSubmatch(e.EpisodeName)
+	if m == nil {
+		return 0
+	}
+	part := strings.ToLower(m[1] + m[2])
+	if n, err := strconv.Atoi(part); err == nil {
+		return n
+	}
+	return partWords[part]
+}
+
+// MultiPartEpisodes returns the groups of episodes that are parts of the same
+// story, sorted by aired season and episode number. Consecutive episodes of
+// the same season are grouped if they share the integer part of a fractional
+// DVD episode number, if they have the same name with consecutive part
+// suffixes or if they have the same name and aired within one day. Episodes
+// that are not part of a multi-part episode are not returned.
+func (s *Series) MultiPartEpisodes() [][]*Episode {
+	episodes := make([]*Episode, 0, len(s.Episodes))
+	for i := range s.Episodes {
+		episodes = append(episodes, &s.Episodes[i])
+	}
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return airedLess(episodes[i], episodes[j])
+	})
+	groups := make([][]*Episode, 0)
+	var group []*Episode
+	for _, ep := range episodes {
+		if len(group) > 0 && sameStory(group[len(group)-1], ep) {
+			group = append(group, ep)
+			continue
+		}
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+		group = []*Episode{ep}
+	}
+	if len(group) > 1 {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// EpisodeParts returns all the parts of the multi-part episode e belongs to.
+// If e is not part of a multi-part episode a slice with only e is returned.
+func (s *Series) EpisodeParts(e *Episode) []*Episode {
+	for _, group := range s.MultiPartEpisodes() {
+		for _, ep := range group {
+			if ep.ID == e.ID && ep.AiredSeason == e.AiredSeason && ep.AiredEpisodeNumber == e.AiredEpisodeNumber {
+				return group
+			}
+		}
+	}
+	return []*Episode{e}
+}
+
+// FormatEpisodeRange formats the aired season and episode numbers of one or
+// more consecutive episodes, like S01E01 or S01E01-E02.
+func FormatEpisodeRange(episodes []*Episode) string {
+	if len(episodes) == 0 {
+		return ""
+	}
+	first, last := episodes[0], episodes[len(episodes)-1]
+	if len(episodes) == 1 || first.AiredEpisodeNumber == last.AiredEpisodeNumber {
+		return fmt.Sprintf("S%02dE%02d", first.AiredSeason, first.AiredEpisodeNumber)
+	}
+	return fmt.Sprintf("S%02dE%02d-E%02d", first.AiredSeason, first.AiredEpisodeNumber, last.AiredEpisodeNumber)
+}
+
+func sameStory(a, b *Episode) bool {
+	if a.AiredSeason != b.AiredSeason || b.AiredEpisodeNumber != a.AiredEpisodeNumber+1 {
+		return false
+	}
+	intA, fracA := math.Modf(a.DvdEpisodeNumber)
+	intB, fracB := math.Modf(b.DvdEpisodeNumber)
+	if fracA > 0 && fracB > 0 && intA == intB && a.DvdSeason == b.DvdSeason {
+		return true
+	}
+	baseA, baseB := a.BaseName(), b.BaseName()
+	if baseA == "" || !strings.EqualFold(baseA, baseB) {
+		return false
+	}
+	partA, partB := a.PartNumber(), b.PartNumber()
+	if partA > 0 && partB == partA+1 {
+		return true
+	}
+	dateA, okA := parseAirDate(a.FirstAired)
+	dateB, okB := parseAirDate(b.FirstAired)
+	return okA && okB && dateB.Sub(dateA).Hours() <= 24
+}
@@ -0,0 +1,57 @@
+package tvdb_test
+
+import (
+	"testing"
+
+	"github.com/pioz/tvdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEpisodePartNumber(t *testing.T) {
+	cases := map[string]int{
+		"The Trial (1)":        1,
+		"The Trial (Part 2)":   2,
+		"The Trial, Part II":   2,
+		"The Trial - Part Two": 2,
+		"The Trial":            0,
+		"Apollo 13":            0,
+		"The Trial (2005)":     0,
+		"The Trial (100)":      0,
+	}
+	for name, part := range cases {
+		e := tvdb.Episode{EpisodeName: name}
+		assert.Equal(t, part, e.PartNumber(), name)
+		if part > 0 {
+			assert.Equal(t, "The Trial", e.BaseName(), name)
+		}
+	}
+	e := tvdb.Episode{EpisodeName: "Pilot", DvdEpisodeNumber: 1.2}
+	assert.Equal(t, 2, e.PartNumber())
+}
+
+func TestSeriesMultiPartEpisodes(t *testing.T) {
+	s := tvdb.Series{ID: 1, Episodes: []tvdb.Episode{
+		{ID: 1, AiredSeason: 1, AiredEpisodeNumber: 1, EpisodeName: "Pilot", DvdSeason: 1, DvdEpisodeNumber: 1.1},
+		{ID: 2, AiredSeason: 1, AiredEpisodeNumber: 2, EpisodeName: "Pilot", DvdSeason: 1, DvdEpisodeNumber: 1.2},
+		{ID: 3, AiredSeason: 1, AiredEpisodeNumber: 3, EpisodeName: "Other"},
+		{ID: 4, AiredSeason: 1, AiredEpisodeNumber: 4, EpisodeName: "Finale (1)"},
+		{ID: 5, AiredSeason: 1, AiredEpisodeNumber: 5, EpisodeName: "Finale (2)"},
+		{ID: 6, AiredSeason: 2, AiredEpisodeNumber: 1, EpisodeName: "Twice", FirstAired: "2001-01-01"},
+		{ID: 7, AiredSeason: 2, AiredEpisodeNumber: 2, EpisodeName: "Twice", FirstAired: "2001-01-02"},
+		{ID: 8, AiredSeason: 2, AiredEpisodeNumber: 3, EpisodeName: "Twice", FirstAired: "2001-01-09"},
+	}}
+	groups := s.MultiPartEpisodes()
+	assert.Equal(t, 3, len(groups))
+	assert.Equal(t, "S01E01-E02", tvdb.FormatEpisodeRange(groups[0]))
+	assert.Equal(t, "S01E04-E05", tvdb.FormatEpisodeRange(groups[1]))
+	assert.Equal(t, "S02E01-E02", tvdb.FormatEpisodeRange(groups[2]))
+	assert.Equal(t, 1, len(s.EpisodeParts(s.GetEpisode(1, 3))))
+	assert.Equal(t, 2, len(s.EpisodeParts(s.GetEpisode(1, 5))))
+
+	// Years in parentheses are not part numbers
+	s = tvdb.Series{ID: 2, Episodes: []tvdb.Episode{
+		{ID: 1, AiredSeason: 0, AiredEpisodeNumber: 1, EpisodeName: "Christmas Special (2005)", FirstAired: "2005-12-25"},
+		{ID: 2, AiredSeason: 0, AiredEpisodeNumber: 2, EpisodeName: "Christmas Special (2006)", FirstAired: "2006-12-25"},
+	}}
+	assert.Empty(t, s.MultiPartEpisodes())
+}
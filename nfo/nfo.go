@@ -0,0 +1,287 @@
+// Package nfo writes Kodi compatible NFO metadata files (tvshow.nfo and the
+// per-episode .nfo files, also read by Jellyfin, Emby and Plex agents) from
+// the structs of the tvdb package, and reads the TVDB ids back from existing
+// NFO files.
+//
+// See https://kodi.wiki/view/NFO_files/TV_shows and
+// https://kodi.wiki/view/NFO_files/Episodes for the format.
+package nfo
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pioz/tvdb"
+)
+
+// TVShow is the root element of a tvshow.nfo file.
+type TVShow struct {
+	XMLName       xml.Name   `xml:"tvshow"`
+	Title         string     `xml:"title"`
+	OriginalTitle string     `xml:"originaltitle,omitempty"`
+	Plot          string     `xml:"plot,omitempty"`
+	MPAA          string     `xml:"mpaa,omitempty"`
+	Premiered     string     `xml:"premiered,omitempty"`
+	Status        string     `xml:"status,omitempty"`
+	Studio        string     `xml:"studio,omitempty"`
+	Runtime       string     `xml:"runtime,omitempty"`
+	Genres        []string   `xml:"genre"`
+	Ratings       *Ratings   `xml:"ratings,omitempty"`
+	UniqueIDs     []UniqueID `xml:"uniqueid"`
+	Thumbs        []Thumb    `xml:"thumb"`
+	Fanart        *Fanart    `xml:"fanart,omitempty"`
+	Actors        []Actor    `xml:"actor"`
+	// Legacy id element, written by old scrapers.
+	ID string `xml:"id,omitempty"`
+}
+
+// EpisodeDetails is the root element of an episode NFO file.
+type EpisodeDetails struct {
+	XMLName        xml.Name   `xml:"episodedetails"`
+	Title          string     `xml:"title"`
+	ShowTitle      string     `xml:"showtitle,omitempty"`
+	Season         int        `xml:"season"`
+	Episode        int        `xml:"episode"`
+	DisplaySeason  int        `xml:"displayseason,omitempty"`
+	DisplayEpisode int        `xml:"displayepisode,omitempty"`
+	Plot           string     `xml:"plot,omitempty"`
+	Aired          string     `xml:"aired,omitempty"`
+	Ratings        *Ratings   `xml:"ratings,omitempty"`
+	UniqueIDs      []UniqueID `xml:"uniqueid"`
+	Credits        []string   `xml:"credits"`
+	Directors      []string   `xml:"director"`
+	Thumbs         []Thumb    `xml:"thumb"`
+	Actors         []Actor    `xml:"actor"`
+	// Legacy id element, written by old scrapers.
+	ID string `xml:"id,omitempty"`
+}
+
+// Ratings holds the ratings of a show or an episode.
+type Ratings struct {
+	Ratings []Rating `xml:"rating"`
+}
+
+// Rating is a rating of a show or an episode.
+type Rating struct {
+	Name    string  `xml:"name,attr"`
+	Max     int     `xml:"max,attr"`
+	Default bool    `xml:"default,attr"`
+	Value   float32 `xml:"value"`
+	Votes   int     `xml:"votes"`
+}
+
+// UniqueID is an id of a show or an episode in an online database.
+type UniqueID struct {
+	Type    string `xml:"type,attr"`
+	Default bool   `xml:"default,attr,omitempty"`
+	Value   string `xml:",chardata"`
+}
+
+// Thumb is an image URL.
+type Thumb struct {
+	Aspect  string `xml:"aspect,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Season  string `xml:"season,attr,omitempty"`
+	Preview string `xml:"preview,attr,omitempty"`
+	URL     string `xml:",chardata"`
+}
+
+// Fanart holds the fanart images of a show.
+type Fanart struct {
+	Thumbs []Thumb `xml:"thumb"`
+}
+
+// Actor is an actor of a show or a guest star of an episode.
+type Actor struct {
+	Name  string `xml:"name"`
+	Role  string `xml:"role,omitempty"`
+	Order int    `xml:"order"`
+	Thumb string `xml:"thumb,omitempty"`
+}
+
+// NewTVShow builds the tvshow.nfo content of the series. Actors and images
+// are taken from the series Actors and Images fields, so they must be
+// retrieved before with the Client methods.
+func NewTVShow(s *tvdb.Series) TVShow {
+	show := TVShow{
+		Title:     s.SeriesName,
+		Plot:      s.Overview,
+		MPAA:      s.Rating,
+		Premiered: s.FirstAired,
+		Status:    s.Status,
+		Studio:    s.Network,
+		Runtime:   s.Runtime,
+		Genres:    s.Genre,
+		Ratings:   newRatings(s.SiteRating, s.SiteRatingCount),
+		UniqueIDs: []UniqueID{{Type: "tvdb", Default: true, Value: strconv.Itoa(s.ID)}},
+	}
+	if s.ImdbID != "" {
+		show.UniqueIDs = append(show.UniqueIDs, UniqueID{Type: "imdb", Value: s.ImdbID})
+	}
+	if s.Zap2itID != "" {
+		show.UniqueIDs = append(show.UniqueIDs, UniqueID{Type: "zap2it", Value: s.Zap2itID})
+	}
+	if s.Banner != "" {
+		show.Thumbs = append(show.Thumbs, Thumb{Aspect: "banner", URL: s.BannerURL()})
+	}
+	for i := range s.Images {
+		image := &s.Images[i]
+		thumb := Thumb{URL: image.URL(), Preview: image.ThumbnailURL()}
+		switch image.KeyType {
+		case "poster":
+			thumb.Aspect = "poster"
+		case "series":
+			thumb.Aspect = "banner"
+		case "season":
+			thumb.Aspect, thumb.Type, thumb.Season = "poster", "season", image.SubKey
+		case "seasonwide":
+			thumb.Aspect, thumb.Type, thumb.Season = "banner", "season", image.SubKey
+		case "fanart":
+			if show.Fanart == nil {
+				show.Fanart = &Fanart{}
+			}
+			show.Fanart.Thumbs = append(show.Fanart.Thumbs, thumb)
+			continue
+		default:
+			continue
+		}
+		show.Thumbs = append(show.Thumbs, thumb)
+	}
+	for i := range s.Actors {
+		actor := &s.Actors[i]
+		show.Actors = append(show.Actors, Actor{Name: actor.Name, Role: actor.Role, Order: actor.SortOrder, Thumb: actor.ImageURL()})
+	}
+	return show
+}
+
+// NewEpisodeDetails builds the NFO content of the episode e of the series s.
+// Specials placed with the airsBefore fields get the display season and
+// episode used by Kodi to sort them.
+func NewEpisodeDetails(s *tvdb.Series, e *tvdb.Episode) EpisodeDetails {
+	ep := EpisodeDetails{
+		Title:     e.EpisodeName,
+		ShowTitle: s.SeriesName,
+		Season:    e.AiredSeason,
+		Episode:   e.AiredEpisodeNumber,
+		Plot:      e.Overview,
+		Aired:     e.FirstAired,
+		Ratings:   newRatings(e.SiteRating, e.SiteRatingCount),
+		UniqueIDs: []UniqueID{{Type: "tvdb", Default: true, Value: strconv.Itoa(e.ID)}},
+		Credits:   e.Writers,
+		Directors: e.Directors,
+	}
+	if e.AiredSeason == 0 {
+		switch {
+		case e.AirsBeforeSeason > 0:
+			ep.DisplaySeason, ep.DisplayEpisode = e.AirsBeforeSeason, e.AirsBeforeEpisode
+		case e.AirsAfterSeason > 0:
+			ep.DisplaySeason, ep.DisplayEpisode = e.AirsAfterSeason, 4096
+		}
+	}
+	if len(ep.Directors) == 0 && e.Director != "" {
+		ep.Directors = []string{e.Director}
+	}
+	if e.ImdbID != "" {
+		ep.UniqueIDs = append(ep.UniqueIDs, UniqueID{Type: "imdb", Value: e.ImdbID})
+	}
+	if url := e.ThumbnailURL(); url != "" {
+		ep.Thumbs = append(ep.Thumbs, Thumb{URL: url})
+	}
+	for i, name := range e.GuestStars {
+		ep.Actors = append(ep.Actors, Actor{Name: name, Order: i})
+	}
+	return ep
+}
+
+// WriteTVShow writes the tvshow.nfo content of the series to w.
+func WriteTVShow(w io.Writer, s *tvdb.Series) error {
+	return write(w, NewTVShow(s))
+}
+
+// WriteEpisode writes the NFO content of the episode e of the series s to w.
+func WriteEpisode(w io.Writer, s *tvdb.Series, e *tvdb.Episode) error {
+	return write(w, NewEpisodeDetails(s, e))
+}
+
+func write(w io.Writer, v interface{}) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(v)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func newRatings(value float32, votes int) *Ratings {
+	if votes == 0 && value == 0 {
+		return nil
+	}
+	return &Ratings{Ratings: []Rating{{Name: "tvdb", Max: 10, Default: true, Value: value, Votes: votes}}}
+}
+
+// ErrNoID is returned by Parse when the NFO file doesn't contain a TVDB id.
+var ErrNoID = errors.New("no tvdb id found")
+
+// IDs holds the ids read from an NFO file.
+type IDs struct {
+	// TVDB id of the series (tvshow.nfo) or of the episode.
+	TVDB   int
+	IMDB   string
+	Zap2it string
+	// Episode is true if the NFO file describes an episode.
+	Episode bool
+}
+
+var tvdbURLRegexp = regexp.MustCompile(`thetvdb\.com/\S*?(?:[?&](?:id|seriesid)=|/series/|/episodes/)(\d+)`)
+
+// Parse reads an NFO file, either a tvshow or an episodedetails document, and
+// returns the ids it contains. NFO files made only of a TVDB URL, as supported
+// by Kodi, are recognized too. If no TVDB id is found ErrNoID is returned.
+func Parse(r io.Reader) (IDs, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return IDs{}, err
+	}
+	var doc struct {
+		XMLName   xml.Name
+		UniqueIDs []UniqueID `xml:"uniqueid"`
+		ID        string     `xml:"id"`
+	}
+	var ids IDs
+	if xml.Unmarshal(data, &doc) == nil {
+		ids.Episode = doc.XMLName.Local == "episodedetails"
+		for _, uid := range doc.UniqueIDs {
+			value := strings.TrimSpace(uid.Value)
+			switch strings.ToLower(uid.Type) {
+			case "tvdb":
+				ids.TVDB, _ = strconv.Atoi(value)
+			case "imdb":
+				ids.IMDB = value
+			case "zap2it":
+				ids.Zap2it = value
+			}
+		}
+		if ids.TVDB == 0 {
+			ids.TVDB, _ = strconv.Atoi(strings.TrimSpace(doc.ID))
+		}
+	}
+	if ids.TVDB == 0 {
+		if m := tvdbURLRegexp.FindSubmatch(data); m != nil {
+			ids.TVDB, _ = strconv.Atoi(string(m[1]))
+		}
+	}
+	if ids.TVDB == 0 {
+		return ids, ErrNoID
+	}
+	return ids, nil
+}
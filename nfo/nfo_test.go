@@ -0,0 +1,75 @@
+package nfo_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pioz/tvdb"
+	"github.com/pioz/tvdb/nfo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteTVShow(t *testing.T) {
+	s := tvdb.Series{
+		ID: 121361, SeriesName: "Game of Thrones", ImdbID: "tt0944947", Zap2itID: "EP01", Network: "HBO",
+		SiteRating: 9.1, SiteRatingCount: 100, Genre: []string{"Drama", "Fantasy"},
+		Actors: []tvdb.Actor{{Name: "Peter Dinklage", Role: "Tyrion", Image: "actors/1.jpg"}},
+		Images: []tvdb.Image{
+			{KeyType: "poster", FileName: "posters/1.jpg"},
+			{KeyType: "fanart", FileName: "fanart/1.jpg"},
+			{KeyType: "season", SubKey: "1", FileName: "seasons/1.jpg"},
+		},
+	}
+	var buf bytes.Buffer
+	err := nfo.WriteTVShow(&buf, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "<?xml"))
+	assert.Contains(t, out, `<uniqueid type="tvdb" default="true">121361</uniqueid>`)
+	assert.Contains(t, out, `<uniqueid type="imdb">tt0944947</uniqueid>`)
+	assert.Contains(t, out, `<uniqueid type="zap2it">EP01</uniqueid>`)
+	assert.Contains(t, out, `<thumb aspect="poster">https://thetvdb.com/banners/posters/1.jpg</thumb>`)
+	assert.Contains(t, out, `<thumb aspect="poster" type="season" season="1">https://thetvdb.com/banners/seasons/1.jpg</thumb>`)
+	assert.Contains(t, out, "<fanart>")
+	assert.Contains(t, out, "<thumb>https://thetvdb.com/banners/actors/1.jpg</thumb>")
+	assert.Contains(t, out, "<value>9.1</value>")
+
+	ids, err := nfo.Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, nfo.IDs{TVDB: 121361, IMDB: "tt0944947", Zap2it: "EP01"}, ids)
+}
+
+func TestWriteEpisode(t *testing.T) {
+	s := tvdb.Series{ID: 1, SeriesName: "Show"}
+	e := tvdb.Episode{ID: 10, EpisodeName: "Special", AiredSeason: 0, AiredEpisodeNumber: 1, AirsBeforeSeason: 2, AirsBeforeEpisode: 3, Director: "Someone", ImdbID: "tt1"}
+	var buf bytes.Buffer
+	err := nfo.WriteEpisode(&buf, &s, &e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	assert.Contains(t, out, "<displayseason>2</displayseason>")
+	assert.Contains(t, out, "<displayepisode>3</displayepisode>")
+	assert.Contains(t, out, "<director>Someone</director>")
+	ids, err := nfo.Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, nfo.IDs{TVDB: 10, IMDB: "tt1", Episode: true}, ids)
+}
+
+func TestParse(t *testing.T) {
+	ids, err := nfo.Parse(strings.NewReader("<tvshow><id>121361</id></tvshow>"))
+	assert.Nil(t, err)
+	assert.Equal(t, 121361, ids.TVDB)
+	ids, err = nfo.Parse(strings.NewReader("https://thetvdb.com/?tab=series&id=121361\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 121361, ids.TVDB)
+	_, err = nfo.Parse(strings.NewReader("<tvshow><title>No ids</title></tvshow>"))
+	assert.Equal(t, nfo.ErrNoID, err)
+}
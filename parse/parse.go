@@ -0,0 +1,192 @@
+// Package parse extracts the series name, the season and episode numbers or the
+// air date from the file names of TV episodes, like
+// Game.of.Thrones.S04E08.720p.mkv, show.4x08.avi or Show - 108 - Title.mp4.
+//
+// The result can be used to find the episode with the tvdb package, see the
+// Client.ResolveFile method.
+package parse
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnrecognized is returned by File when the file name doesn't contain a
+// series name and an episode reference.
+var ErrUnrecognized = errors.New("unrecognized file name")
+
+// Result holds the information extracted from a file name.
+type Result struct {
+	// Name of the series, with dots and underscores replaced by spaces.
+	Name string
+	// Year of the series, if present in the file name like "Show (2019)".
+	Year int
+	// Season and episode numbers. Episodes has more than one element for
+	// multi-episode files like S01E01-E03 or S01E01E02.
+	Season   int
+	Episodes []int
+	// Absolute episode numbers, used by anime releases like "Show - 108".
+	// A three or four digit number can be both an absolute number and a
+	// season and episode number (108 is 1x08), so both are filled.
+	Absolute []int
+	// Air date for daily shows like Show.2024.03.05.mkv.
+	AirDate time.Time
+	// File extension without the dot.
+	Extension string
+}
+
+// HasEpisode returns true if the result references episodes by season and
+// episode numbers.
+func (r *Result) HasEpisode() bool {
+	return len(r.Episodes) > 0
+}
+
+// HasAirDate returns true if the result references episodes by air date.
+func (r *Result) HasAirDate() bool {
+	return !r.AirDate.IsZero()
+}
+
+var (
+	groupRegexp    = regexp.MustCompile(`\[[^\]]*\]|\{[^}]*\}`)
+	seasonRegexp   = regexp.MustCompile(`(?i)\bs(\d{1,2}) ?e(\d{1,4})((?: ?-? ?e\d{1,4}|-\d{1,4}\b)*)`)
+	crossRegexp    = regexp.MustCompile(`(?i)\b(\d{1,2})x(\d{2,3})((?:-(?:\d{1,2}x)?\d{2,3})*)\b`)
+	dateRegexp     = regexp.MustCompile(`\b((?:19|20)\d{2})[ -](\d{2})[ -](\d{2})\b`)
+	absoluteRegexp = regexp.MustCompile(`(?:^|\s)(?:-\s+)?(?:e|ep|episode\s*)?(\d{2,4})(?:v\d)?(?:\s*-\s*(\d{2,4}))?(?:\s|$)`)
+	yearRegexp     = regexp.MustCompile(`\s\(?((?:19|20)\d{2})\)?$`)
+	numberRegexp   = regexp.MustCompile(`\d+`)
+)
+
+// File parses the file name, which may include directories, and returns the
+// information found. If the name doesn't contain an episode reference
+// ErrUnrecognized is returned.
+func File(name string) (Result, error) {
+	var r Result
+	base := filepath.Base(name)
+	if ext := filepath.Ext(base); isExtension(ext) {
+		r.Extension = strings.ToLower(ext[1:])
+		base = strings.TrimSuffix(base, ext)
+	}
+	clean := groupRegexp.ReplaceAllString(base, " ")
+	clean = strings.NewReplacer(".", " ", "_", " ").Replace(clean)
+	clean = strings.Join(strings.Fields(clean), " ")
+
+	end := -1
+	if m := seasonRegexp.FindStringSubmatchIndex(clean); m != nil {
+		end = m[0]
+		r.Season = atoi(clean[m[2]:m[3]])
+		r.Episodes = episodeRange(atoi(clean[m[4]:m[5]]), clean[m[6]:m[7]])
+	} else if m := crossRegexp.FindStringSubmatchIndex(clean); m != nil {
+		end = m[0]
+		r.Season = atoi(clean[m[2]:m[3]])
+		r.Episodes = episodeRange(atoi(clean[m[4]:m[5]]), crossExtra(clean[m[6]:m[7]]))
+	} else if m := dateRegexp.FindStringSubmatchIndex(clean); m != nil {
+		date, err := time.Parse("2006-01-02", clean[m[2]:m[3]]+"-"+clean[m[4]:m[5]]+"-"+clean[m[6]:m[7]])
+		if err == nil {
+			end = m[0]
+			r.AirDate = date
+		}
+	}
+	if end < 0 {
+		for _, m := range absoluteRegexp.FindAllStringSubmatchIndex(clean, -1) {
+			// The first word is always part of the series name.
+			if m[2] == 0 || isYear(clean[m[2]:m[3]]) {
+				continue
+			}
+			end = m[0]
+			first := atoi(clean[m[2]:m[3]])
+			last := first
+			if m[4] >= 0 {
+				last = atoi(clean[m[4]:m[5]])
+			}
+			for n := first; n <= last; n++ {
+				r.Absolute = append(r.Absolute, n)
+			}
+			if first >= 100 {
+				r.Season = first / 100
+				r.Episodes = episodeRange(first%100, "")
+				if last > first && last/100 == r.Season {
+					r.Episodes = episodeRange(first%100, "-"+strconv.Itoa(last%100))
+				}
+			}
+			break
+		}
+	}
+	if end < 0 {
+		return r, ErrUnrecognized
+	}
+
+	name = strings.TrimRight(strings.TrimSpace(clean[:end]), " -")
+	if m := yearRegexp.FindStringSubmatchIndex(name); m != nil && m[0] > 0 {
+		r.Year = atoi(name[m[2]:m[3]])
+		name = strings.TrimRight(strings.TrimSpace(name[:m[0]]), " -")
+	}
+	r.Name = name
+	if r.Name == "" {
+		return r, ErrUnrecognized
+	}
+	return r, nil
+}
+
+// episodeRange returns the episode numbers starting from first and followed
+// by extra, that can be a list (E02E03) or a range (-E03 or -03).
+func episodeRange(first int, extra string) []int {
+	numbers := numberRegexp.FindAllString(extra, -1)
+	episodes := []int{first}
+	if len(numbers) == 0 {
+		return episodes
+	}
+	if strings.Contains(extra, "-") {
+		last := atoi(numbers[len(numbers)-1])
+		for n := first + 1; n <= last; n++ {
+			episodes = append(episodes, n)
+		}
+		return episodes
+	}
+	for _, n := range numbers {
+		episodes = append(episodes, atoi(n))
+	}
+	return episodes
+}
+
+// crossExtra converts the extra part of a 4x08-4x09 reference in a range.
+func crossExtra(extra string) string {
+	if extra == "" {
+		return ""
+	}
+	parts := strings.Split(extra, "x")
+	return "-" + numberRegexp.FindString(parts[len(parts)-1])
+}
+
+var videoExtensions = map[string]bool{
+	".avi": true, ".divx": true, ".m2ts": true, ".m4v": true, ".mkv": true,
+	".mov": true, ".mp4": true, ".mpeg": true, ".mpg": true, ".ogm": true,
+	".ts": true, ".webm": true, ".wmv": true,
+}
+
+var sidecarExtensions = map[string]bool{
+	".srt": true, ".sub": true, ".ass": true, ".nfo": true,
+}
+
+// IsVideo returns true if the file name has the extension of a video file.
+func IsVideo(name string) bool {
+	return videoExtensions[strings.ToLower(filepath.Ext(name))]
+}
+
+func isExtension(ext string) bool {
+	ext = strings.ToLower(ext)
+	return videoExtensions[ext] || sidecarExtensions[ext]
+}
+
+func isYear(s string) bool {
+	n := atoi(s)
+	return len(s) == 4 && n >= 1900 && n < 2100
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
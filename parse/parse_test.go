@@ -0,0 +1,57 @@
+package parse_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pioz/tvdb/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFile(t *testing.T) {
+	cases := []struct {
+		name     string
+		series   string
+		year     int
+		season   int
+		episodes []int
+		absolute []int
+	}{
+		{"Game.of.Thrones.S04E08.720p.mkv", "Game of Thrones", 0, 4, []int{8}, nil},
+		{"/media/tv/show.4x08.avi", "show", 0, 4, []int{8}, nil},
+		{"Show - 108 - Title.mp4", "Show", 0, 1, []int{8}, []int{108}},
+		{"Doctor.Who.(2005).s01e01e02.mkv", "Doctor Who", 2005, 1, []int{1, 2}, nil},
+		{"Doctor Who 2005 S01E01-E03.mkv", "Doctor Who", 2005, 1, []int{1, 2, 3}, nil},
+		{"The_Office_US_S02E01-02.mkv", "The Office US", 0, 2, []int{1, 2}, nil},
+		{"show.1x01-1x02.avi", "show", 0, 1, []int{1, 2}, nil},
+		{"[Group] One Piece - 45 [1080p].mkv", "One Piece", 0, 0, nil, []int{45}},
+		{"1923.S01E01.mkv", "1923", 0, 1, []int{1}, nil},
+	}
+	for _, c := range cases {
+		r, err := parse.File(c.name)
+		if err != nil {
+			t.Fatal(c.name, err)
+		}
+		assert.Equal(t, c.series, r.Name, c.name)
+		assert.Equal(t, c.year, r.Year, c.name)
+		assert.Equal(t, c.season, r.Season, c.name)
+		assert.Equal(t, c.episodes, r.Episodes, c.name)
+		assert.Equal(t, c.absolute, r.Absolute, c.name)
+	}
+}
+
+func TestFileAirDate(t *testing.T) {
+	r, err := parse.File("The.Daily.Show.2024.03.05.Guest.Name.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "The Daily Show", r.Name)
+	assert.Equal(t, "mp4", r.Extension)
+	assert.True(t, r.HasAirDate())
+	assert.Equal(t, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), r.AirDate)
+}
+
+func TestFileUnrecognized(t *testing.T) {
+	_, err := parse.File("holidays.mkv")
+	assert.Equal(t, parse.ErrUnrecognized, err)
+}
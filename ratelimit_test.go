@@ -0,0 +1,26 @@
+package tvdb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pioz/tvdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := tvdb.NewRateLimiter(50, 2)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		assert.Nil(t, l.Wait(context.Background()))
+	}
+	// Two requests of the burst, then two every 20ms.
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l = tvdb.NewRateLimiter(0.001, 1)
+	assert.Nil(t, l.Wait(ctx))
+	assert.Equal(t, context.Canceled, l.Wait(ctx))
+}
@@ -0,0 +1,252 @@
+// Package rename organizes a library of TV episode files: every video file of
+// a directory is matched to its TVDB episodes and moved to a path built from
+// a template, like
+//
+//	Game of Thrones (2011)/Season 04/Game of Thrones - S04E08 - The Mountain and the Viper.mkv
+//
+// Renames can be simulated with a dry run and every applied rename is
+// recorded in an undo log that can be used to restore the original names.
+package rename
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/pioz/tvdb"
+	"github.com/pioz/tvdb/parse"
+)
+
+// Resolver matches a file name to its series and episodes. tvdb.Client
+// implements it with the ResolveFile method; Plan resolves every file, so the
+// client should have a Cache to not repeat the requests of files of the same
+// series.
+type Resolver interface {
+	ResolveFile(name string) (tvdb.Series, []tvdb.Episode, float64, error)
+}
+
+// ErrLowConfidence is the error of an Operation when the file was matched
+// with a confidence lower than Renamer.MinConfidence.
+var ErrLowConfidence = errors.New("match confidence too low")
+
+// Renamer renames and moves the episode files.
+type Renamer struct {
+	Resolver Resolver
+	// Template of the destination paths, relative to Dest. See Format for
+	// the placeholders. If empty DefaultTemplate is used.
+	Template string
+	// Destination directory. If empty files are organized in the directory
+	// passed to Plan.
+	Dest string
+	// If DryRun is true Apply doesn't touch the filesystem.
+	DryRun bool
+	// Files matched with a lower confidence are not renamed.
+	MinConfidence float64
+	// Path of the undo log. If empty no log is written.
+	UndoLog string
+}
+
+// Operation is the rename of a file.
+type Operation struct {
+	From       string
+	To         string
+	Series     tvdb.Series
+	Episodes   []tvdb.Episode
+	Confidence float64
+	// Err is set if the file can't be matched or renamed.
+	Err error
+}
+
+// Skip returns true if the operation will not rename the file, because of an
+// error or because the file already has the right name.
+func (op *Operation) Skip() bool {
+	return op.Err != nil || op.From == op.To
+}
+
+// Plan walks dir and returns an operation for every video file found (see
+// parse.IsVideo).
+// Files that can't be matched have the Err field set.
+func (r *Renamer) Plan(dir string) ([]Operation, error) {
+	template := r.Template
+	if template == "" {
+		template = DefaultTemplate
+	}
+	dest := r.Dest
+	if dest == "" {
+		dest = dir
+	}
+	ops := make([]Operation, 0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !parse.IsVideo(path) {
+			return nil
+		}
+		op := Operation{From: path}
+		op.Series, op.Episodes, op.Confidence, op.Err = r.Resolver.ResolveFile(filepath.Base(path))
+		if op.Err == nil && op.Confidence < r.MinConfidence {
+			op.Err = ErrLowConfidence
+		}
+		if op.Err == nil {
+			var to string
+			to, op.Err = Format(template, &op.Series, op.Episodes, filepath.Ext(path))
+			op.To = filepath.Join(dest, filepath.FromSlash(to))
+		}
+		ops = append(ops, op)
+		return nil
+	})
+	return ops, err
+}
+
+// Apply performs the operations, creating the destination directories. An
+// existing file is never overwritten. A rename whose undo log entry can't be
+// written is reverted. The error of each failed rename is stored in the
+// operation and the first one is returned.
+func (r *Renamer) Apply(ops []Operation) error {
+	if r.DryRun {
+		return nil
+	}
+	var log io.WriteCloser
+	if r.UndoLog != "" {
+		f, err := os.OpenFile(r.UndoLog, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		log = f
+		defer log.Close()
+	}
+	var first error
+	for i := range ops {
+		op := &ops[i]
+		if op.Skip() {
+			continue
+		}
+		op.Err = move(op.From, op.To)
+		if op.Err == nil && log != nil {
+			// A rename missing from the log could not be undone, so it is
+			// reverted if the entry can't be written.
+			if err := json.NewEncoder(log).Encode(logEntry{From: op.From, To: op.To, Time: time.Now()}); err != nil {
+				op.Err = errors.Join(fmt.Errorf("undo log: %w", err), move(op.To, op.From))
+			}
+		}
+		if op.Err != nil && first == nil {
+			first = op.Err
+		}
+	}
+	return first
+}
+
+// Run plans and applies the renames of the files in dir.
+func (r *Renamer) Run(dir string) ([]Operation, error) {
+	ops, err := r.Plan(dir)
+	if err != nil {
+		return ops, err
+	}
+	return ops, r.Apply(ops)
+}
+
+type logEntry struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	Time time.Time `json:"time"`
+}
+
+// Undo reverts the renames recorded in the undo log, from the last to the
+// first, and removes the log. Entries that can't be reverted are kept in the
+// log and the first error is returned.
+func Undo(logPath string) error {
+	f, err := os.Open(logPath)
+	if err != nil {
+		return err
+	}
+	entries := make([]logEntry, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry logEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			f.Close()
+			return fmt.Errorf("invalid undo log entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	f.Close()
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	var first error
+	failed := make([]logEntry, 0)
+	for i := len(entries) - 1; i >= 0; i-- {
+		if err := move(entries[i].To, entries[i].From); err != nil {
+			failed = append([]logEntry{entries[i]}, failed...)
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	if len(failed) == 0 {
+		return os.Remove(logPath)
+	}
+	f, err = os.Create(logPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, entry := range failed {
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return first
+}
+
+// move renames from to to, creating the directories of to. If the rename
+// fails because the paths are on different filesystems the file is copied
+// and then removed.
+func move(from, to string) error {
+	if _, err := os.Lstat(to); err == nil {
+		return fmt.Errorf("%s already exists", to)
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	err := os.Rename(from, to)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := copyFile(from, to); err != nil {
+		os.Remove(to)
+		return err
+	}
+	return os.Remove(from)
+}
+
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(to, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,100 @@
+package rename_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pioz/tvdb"
+	"github.com/pioz/tvdb/rename"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeResolver struct{}
+
+func (fakeResolver) ResolveFile(name string) (tvdb.Series, []tvdb.Episode, float64, error) {
+	s := tvdb.Series{ID: 1, SeriesName: "Show: The Return", FirstAired: "2011-04-17"}
+	switch name {
+	case "show.s01e01.mkv":
+		return s, []tvdb.Episode{{ID: 1, AiredSeason: 1, AiredEpisodeNumber: 1, EpisodeName: "Who/What?"}}, 1, nil
+	case "show.s01e02e03.avi":
+		return s, []tvdb.Episode{
+			{ID: 2, AiredSeason: 1, AiredEpisodeNumber: 2, EpisodeName: "Finale (1)"},
+			{ID: 3, AiredSeason: 1, AiredEpisodeNumber: 3, EpisodeName: "Finale (2)"},
+		}, 0.9, nil
+	case "other.s01e01.mkv":
+		return s, []tvdb.Episode{{ID: 1}}, 0.2, nil
+	}
+	return tvdb.Series{}, nil, 0, errors.New("not found")
+}
+
+func TestFormat(t *testing.T) {
+	s := tvdb.Series{ID: 1, SeriesName: "Show"}
+	path, err := rename.Format(rename.DefaultTemplate, &s, []tvdb.Episode{{AiredSeason: 1, AiredEpisodeNumber: 5, EpisodeName: "Pilot"}}, ".mkv")
+	assert.Nil(t, err)
+	assert.Equal(t, "Show/Season 01/Show - S01E05 - Pilot.mkv", path)
+	_, err = rename.Format("{Unknown}", &s, []tvdb.Episode{{}}, ".mkv")
+	assert.NotNil(t, err)
+	assert.Equal(t, "A - B C", rename.Sanitize("A / B  C?"))
+}
+
+func TestRenamer(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"show.s01e01.mkv", "show.s01e02e03.avi", "other.s01e01.mkv", "unknown.mkv", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	undoLog := filepath.Join(t.TempDir(), "undo.log")
+	r := rename.Renamer{
+		Resolver:      fakeResolver{},
+		Template:      "{SeriesName} ({Year})/Season {Season:02}/{SeriesName} - {EpisodeRange} - {EpisodeName}.{ext}",
+		MinConfidence: 0.5,
+		UndoLog:       undoLog,
+		DryRun:        true,
+	}
+	ops, err := r.Run(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(ops))
+	assert.FileExists(t, filepath.Join(dir, "show.s01e01.mkv"))
+
+	r.DryRun = false
+	ops, err = r.Run(dir)
+	assert.Nil(t, err)
+	renamed := 0
+	for _, op := range ops {
+		if !op.Skip() {
+			renamed++
+		}
+	}
+	assert.Equal(t, 2, renamed)
+	assert.FileExists(t, filepath.Join(dir, "Show - The Return (2011)", "Season 01", "Show - The Return - S01E01 - Who-What.mkv"))
+	assert.FileExists(t, filepath.Join(dir, "Show - The Return (2011)", "Season 01", "Show - The Return - S01E02-E03 - Finale.avi"))
+	assert.FileExists(t, filepath.Join(dir, "other.s01e01.mkv"))
+
+	err = rename.Undo(undoLog)
+	assert.Nil(t, err)
+	assert.FileExists(t, filepath.Join(dir, "show.s01e01.mkv"))
+	assert.FileExists(t, filepath.Join(dir, "show.s01e02e03.avi"))
+	assert.NoFileExists(t, undoLog)
+}
+
+func TestRenamerUndoLogFailure(t *testing.T) {
+	// Writes to /dev/full always fail.
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("requires /dev/full")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "show.s01e01.mkv"), []byte("show"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := rename.Renamer{Resolver: fakeResolver{}, Template: rename.DefaultTemplate, UndoLog: "/dev/full"}
+	ops, err := r.Run(dir)
+	assert.ErrorContains(t, err, "undo log")
+	if assert.Len(t, ops, 1) {
+		assert.Equal(t, err, ops[0].Err)
+		assert.NoFileExists(t, ops[0].To)
+	}
+	assert.FileExists(t, filepath.Join(dir, "show.s01e01.mkv"), "the rename is reverted")
+}
@@ -0,0 +1,131 @@
+package rename
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pioz/tvdb"
+)
+
+// DefaultTemplate is the template used when Renamer.Template is empty.
+const DefaultTemplate = "{SeriesName} ({Year})/Season {Season:02}/{SeriesName} - S{Season:02}E{Episode:02} - {EpisodeName}.{ext}"
+
+var placeholderRegexp = regexp.MustCompile(`\{(\w+)(?::(\d+))?\}`)
+
+// Format builds the relative destination path of a file from the template.
+// Available placeholders are:
+//
+//	{SeriesName}    name of the series
+//	{SeriesID}      TVDB id of the series
+//	{Year}          year of the first aired episode of the series
+//	{Season}        aired season number
+//	{Episode}       aired number of the first episode
+//	{EpisodeRange}  S01E01 or S01E01-E02 for multi-episode files
+//	{Absolute}      absolute number of the first episode
+//	{EpisodeName}   name of the episode, or of the parts of a multi-episode file
+//	{EpisodeID}     TVDB id of the first episode
+//	{AirDate}       first aired date of the first episode (2006-01-02)
+//	{ext}           extension of the original file
+//
+// Numeric placeholders accept a width, {Season:02} pads the season with zeros
+// to two digits. Values are sanitized so that they are safe in file names,
+// while slashes in the template separate directories.
+func Format(template string, s *tvdb.Series, episodes []tvdb.Episode, ext string) (string, error) {
+	if len(episodes) == 0 {
+		return "", fmt.Errorf("no episodes to format")
+	}
+	first := &episodes[0]
+	year := ""
+	if len(s.FirstAired) >= 4 {
+		year = s.FirstAired[:4]
+	}
+	pointers := make([]*tvdb.Episode, len(episodes))
+	for i := range episodes {
+		pointers[i] = &episodes[i]
+	}
+	values := map[string]interface{}{
+		"SeriesName":   s.SeriesName,
+		"SeriesID":     s.ID,
+		"Year":         year,
+		"Season":       first.AiredSeason,
+		"Episode":      first.AiredEpisodeNumber,
+		"EpisodeRange": tvdb.FormatEpisodeRange(pointers),
+		"Absolute":     first.AbsoluteNumber,
+		"EpisodeName":  episodesName(episodes),
+		"EpisodeID":    first.ID,
+		"AirDate":      first.FirstAired,
+		"ext":          strings.TrimPrefix(ext, "."),
+	}
+
+	var err error
+	segments := strings.Split(template, "/")
+	for i, segment := range segments {
+		segments[i] = placeholderRegexp.ReplaceAllStringFunc(segment, func(m string) string {
+			sub := placeholderRegexp.FindStringSubmatch(m)
+			value, ok := values[sub[1]]
+			if !ok {
+				err = fmt.Errorf("unknown placeholder %s", m)
+				return m
+			}
+			if n, isInt := value.(int); isInt {
+				width, _ := strconv.Atoi(sub[2])
+				return fmt.Sprintf("%0*d", width, n)
+			}
+			return Sanitize(value.(string))
+		})
+		segments[i] = cleanSegment(segments[i])
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// episodesName returns the name of the episodes of a file: the name of the
+// episode, the common name of the parts of a multi-part episode or the names
+// joined with " & ".
+func episodesName(episodes []tvdb.Episode) string {
+	if len(episodes) == 1 {
+		return episodes[0].EpisodeName
+	}
+	base := episodes[0].BaseName()
+	names := make([]string, 0, len(episodes))
+	for i := range episodes {
+		if episodes[i].BaseName() != base {
+			base = ""
+		}
+		names = append(names, episodes[i].EpisodeName)
+	}
+	if base != "" {
+		return base
+	}
+	return strings.Join(names, " & ")
+}
+
+var unsafeReplacer = strings.NewReplacer(
+	"/", "-", "\\", "-", ":", " -", "*", "", "?", "", "\"", "'", "<", "", ">", "", "|", "-",
+)
+
+// Sanitize makes the string safe to be used as a file name on every common
+// filesystem, removing the reserved characters and the control characters.
+func Sanitize(s string) string {
+	s = unsafeReplacer.Replace(s)
+	s = strings.Map(func(r rune) rune {
+		if r < 32 || r == 127 {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// cleanSegment removes from a path segment the leading and trailing spaces
+// and the trailing dots, that are not allowed on Windows, and empty
+// parentheses left by missing values, like the year in "Show ()".
+func cleanSegment(s string) string {
+	s = strings.ReplaceAll(s, "()", "")
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.TrimRight(s, ". ")
+}
@@ -0,0 +1,98 @@
+package tvdb
+
+import (
+	"errors"
+
+	"github.com/pioz/tvdb/parse"
+)
+
+// ErrEpisodeNotFound is returned by ResolveFile when the series is found but
+// the episodes referenced by the file name are not.
+var ErrEpisodeNotFound = errors.New("episode not found")
+
+// ambiguousScore scales the confidence of a match that reads a bare number
+// like 108 as season and episode (1x08) while it could be an absolute number.
+const ambiguousScore = 0.75
+
+// ResolveFile parses the file name of an episode (see the parse package),
+// searches the series with BestSearchWithHints and returns the series, with
+// all its episodes retrieved, and the episodes referenced by the file name.
+// The last value is the confidence of the match between 0 and 1: the score of
+// the series search lowered by the fraction of referenced episodes not found.
+// A bare number like 108 is taken as absolute number if the series episodes
+// have absolute numbers, otherwise as season and episode (1x08) with a lower
+// confidence.
+func (c *Client) ResolveFile(name string) (Series, []Episode, float64, error) {
+	r, err := parse.File(name)
+	if err != nil {
+		return Series{}, nil, 0, err
+	}
+	series, score, err := c.BestSearchWithHints(r.Name, SearchHints{Year: r.Year})
+	if err != nil {
+		return Series{}, nil, 0, err
+	}
+	err = c.GetSeriesEpisodes(&series, nil)
+	if err != nil {
+		return series, nil, 0, err
+	}
+	episodes, wanted, ambiguous := resolveEpisodes(&series, r)
+	if len(episodes) == 0 {
+		return series, nil, 0, ErrEpisodeNotFound
+	}
+	if ambiguous {
+		score *= ambiguousScore
+	}
+	if wanted > len(episodes) {
+		score *= float64(len(episodes)) / float64(wanted)
+	}
+	return series, episodes, score, nil
+}
+
+// resolveEpisodes returns the episodes of the series referenced by the parse
+// result, how many episodes were referenced and if they were found reading a
+// number that can also be an absolute number as season and episode.
+func resolveEpisodes(s *Series, r parse.Result) ([]Episode, int, bool) {
+	episodes := make([]Episode, 0)
+	if r.HasAirDate() {
+		for _, ep := range s.EpisodesOn(r.AirDate) {
+			episodes = append(episodes, *ep)
+		}
+		return episodes, len(episodes), false
+	}
+	ambiguous := len(r.Episodes) > 0 && len(r.Absolute) > 0
+	if ambiguous && hasAbsoluteNumbers(s) {
+		if episodes := absoluteEpisodes(s, r.Absolute); len(episodes) > 0 {
+			return episodes, len(r.Absolute), false
+		}
+	}
+	for _, number := range r.Episodes {
+		if ep := s.GetEpisode(r.Season, number); ep != nil {
+			episodes = append(episodes, *ep)
+		}
+	}
+	if len(episodes) > 0 || len(r.Absolute) == 0 {
+		return episodes, len(r.Episodes), ambiguous
+	}
+	return absoluteEpisodes(s, r.Absolute), len(r.Absolute), false
+}
+
+func absoluteEpisodes(s *Series, numbers []int) []Episode {
+	episodes := make([]Episode, 0)
+	for _, number := range numbers {
+		if ep := s.GetAbsoluteEpisode(number); ep != nil {
+			episodes = append(episodes, *ep)
+		}
+	}
+	return episodes
+}
+
+// hasAbsoluteNumbers returns true if the episodes of the series have an
+// absolute number.
+func hasAbsoluteNumbers(s *Series) bool {
+	for i := range s.Episodes {
+		if s.Episodes[i].AbsoluteNumber != 0 {
+			return true
+		}
+	}
+	return false
+}
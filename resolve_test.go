@@ -0,0 +1,44 @@
+package tvdb_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/pioz/tvdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveFileAmbiguousNumber(t *testing.T) {
+	cache := tvdb.NewDiskCache(t.TempDir(), true)
+	seeds := map[string]string{
+		tvdb.CacheKey("/search/series", url.Values{"name": {"One Piece"}}, "en"):   `{"data":[{"id":1,"seriesName":"One Piece"}]}`,
+		tvdb.CacheKey("/series/1/episodes/query", url.Values{"page": {"1"}}, "en"): `{"data":[{"id":11,"airedSeason":1,"airedEpisodeNumber":8,"absoluteNumber":8},{"id":12,"airedSeason":5,"airedEpisodeNumber":3,"absoluteNumber":108}]}`,
+		tvdb.CacheKey("/search/series", url.Values{"name": {"Show"}}, "en"):        `{"data":[{"id":2,"seriesName":"Show"}]}`,
+		tvdb.CacheKey("/series/2/episodes/query", url.Values{"page": {"1"}}, "en"): `{"data":[{"id":21,"airedSeason":1,"airedEpisodeNumber":8}]}`,
+	}
+	for key, data := range seeds {
+		if err := cache.Seed(key, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := tvdb.Client{Language: "en", Cache: cache}
+
+	// The series has absolute numbers, so 108 is the absolute number
+	_, episodes, confidence, err := c.ResolveFile("One Piece - 108.mkv")
+	if assert.NoError(t, err) && assert.Len(t, episodes, 1) {
+		assert.Equal(t, 12, episodes[0].ID)
+		assert.Equal(t, 1.0, confidence)
+	}
+	_, episodes, confidence, err = c.ResolveFile("One Piece - S01E08.mkv")
+	if assert.NoError(t, err) && assert.Len(t, episodes, 1) {
+		assert.Equal(t, 11, episodes[0].ID)
+		assert.Equal(t, 1.0, confidence)
+	}
+
+	// Without absolute numbers 108 is read as 1x08 with a lower confidence
+	_, episodes, confidence, err = c.ResolveFile("Show - 108.mkv")
+	if assert.NoError(t, err) && assert.Len(t, episodes, 1) {
+		assert.Equal(t, 21, episodes[0].ID)
+		assert.Less(t, confidence, 1.0)
+	}
+}
@@ -0,0 +1,267 @@
+package tvdb
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrNoResults is returned by BestSearch when the search finds no series.
+var ErrNoResults = errors.New("no results found")
+
+// SearchHints holds optional information used to rank the series returned by
+// a search. Zero values are ignored.
+type SearchHints struct {
+	// Year of the first aired episode.
+	Year int
+	// Network name, compared case insensitively.
+	Network string
+	// IMDB id of the series. If set the series is searched by IMDB id first.
+	ImdbID string
+	// Country code like US or UK, matched against the suffix that TVDB adds
+	// to the names of remakes, like "The Office (US)".
+	Country string
+}
+
+// SearchMatch is a series returned by a search with its confidence score
+// between 0 and 1.
+type SearchMatch struct {
+	Series Series
+	Score  float64
+}
+
+// BestSearchWithHints returns the series that best matches the name q and the
+// hints, together with a confidence score between 0 and 1. A year in
+// parentheses at the end of q, like "Doctor Who (2005)", is used as year hint.
+// If no series is found ErrNoResults is returned.
+func (c *Client) BestSearchWithHints(q string, hints SearchHints) (Series, float64, error) {
+	if hints.ImdbID != "" {
+		res, err := c.SearchByImdbID(hints.ImdbID)
+		if err != nil && !HaveCodeError(404, err) {
+			return Series{}, 0, err
+		}
+		if len(res) > 0 {
+			return res[0], 1, nil
+		}
+	}
+	name, year, _ := splitTitle(q)
+	if hints.Year == 0 {
+		hints.Year = year
+	}
+	res, err := c.SearchByName(name)
+	if HaveCodeError(404, err) {
+		// The api responds 404 when no series matches the name.
+		return Series{}, 0, ErrNoResults
+	}
+	if err != nil {
+		return Series{}, 0, err
+	}
+	matches := RankSearchResults(q, res, hints)
+	if len(matches) == 0 {
+		return Series{}, 0, ErrNoResults
+	}
+	return matches[0].Series, matches[0].Score, nil
+}
+
+// RankSearchResults scores the series against the name q and the hints and
+// returns them sorted from the best to the worst match. Names and aliases are
+// normalized (case, punctuation, diacritics, leading articles and year or
+// country suffixes are ignored) and compared with both the Levenshtein
+// distance and the overlap of their words.
+func RankSearchResults(q string, results []Series, hints SearchHints) []SearchMatch {
+	name, year, country := splitTitle(q)
+	if hints.Year == 0 {
+		hints.Year = year
+	}
+	if hints.Country == "" {
+		hints.Country = country
+	}
+	query := normalizeTitle(name)
+	matches := make([]SearchMatch, 0, len(results))
+	for _, series := range results {
+		matches = append(matches, SearchMatch{Series: series, Score: scoreSeries(query, series, hints)})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+func scoreSeries(query string, series Series, hints SearchHints) float64 {
+	if hints.ImdbID != "" && series.ImdbID == hints.ImdbID {
+		return 1
+	}
+	name, _, seriesCountry := splitTitle(series.SeriesName)
+	score := titleSimilarity(query, normalizeTitle(name))
+	for _, alias := range series.Aliases {
+		name, _, _ := splitTitle(alias)
+		// Prefer the series name when it matches as well as an alias.
+		if sim := titleSimilarity(query, normalizeTitle(name)) * 0.995; sim > score {
+			score = sim
+		}
+	}
+	if hints.Year > 0 && len(series.FirstAired) >= 4 {
+		if year, err := strconv.Atoi(series.FirstAired[:4]); err == nil {
+			switch diff := year - hints.Year; {
+			case diff == 0:
+				score += 0.15
+			case diff > 1 || diff < -1:
+				score -= 0.15
+			}
+		}
+	}
+	if hints.Network != "" && series.Network != "" {
+		if strings.EqualFold(hints.Network, series.Network) {
+			score += 0.1
+		} else {
+			score -= 0.05
+		}
+	}
+	if hints.Country != "" && seriesCountry != "" {
+		if strings.EqualFold(hints.Country, seriesCountry) {
+			score += 0.1
+		} else {
+			score -= 0.1
+		}
+	}
+	return clampScore(score)
+}
+
+var titleSuffixRegexp = regexp.MustCompile(`\s*\((\d{4}|[A-Za-z]{2,3})\)\s*$`)
+
+// splitTitle removes from a title the year and the country suffixes in
+// parentheses, like "The Office (US)" or "Doctor Who (2005)", and returns them.
+func splitTitle(title string) (string, int, string) {
+	var year int
+	var country string
+	for {
+		m := titleSuffixRegexp.FindStringSubmatchIndex(title)
+		if m == nil {
+			return strings.TrimSpace(title), year, country
+		}
+		suffix := title[m[2]:m[3]]
+		if n, err := strconv.Atoi(suffix); err == nil {
+			year = n
+		} else {
+			country = strings.ToUpper(suffix)
+		}
+		title = title[:m[0]]
+	}
+}
+
+var diacritics = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a", "æ", "ae",
+	"ç", "c", "è", "e", "é", "e", "ê", "e", "ë", "e", "ì", "i", "í", "i",
+	"î", "i", "ï", "i", "ñ", "n", "ò", "o", "ó", "o", "ô", "o", "õ", "o",
+	"ö", "o", "ø", "o", "œ", "oe", "ù", "u", "ú", "u", "û", "u", "ü", "u",
+	"ý", "y", "ÿ", "y", "ß", "ss",
+)
+
+// normalizeTitle lowers the title and removes diacritics, punctuation and
+// leading articles.
+func normalizeTitle(title string) string {
+	title = diacritics.Replace(strings.ToLower(title))
+	title = strings.ReplaceAll(title, "&", " and ")
+	title = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return r
+		case r == '\'' || r == '’':
+			return -1
+		}
+		return ' '
+	}, title)
+	words := strings.Fields(title)
+	if len(words) > 1 {
+		switch words[0] {
+		case "the", "a", "an":
+			words = words[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// titleSimilarity returns a similarity between 0 and 1 of two normalized
+// titles, the best of the Levenshtein ratio and the words overlap.
+func titleSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 0
+	}
+	lev := 1 - float64(levenshtein(ra, rb))/float64(longest)
+	tokens := tokenSimilarity(strings.Fields(a), strings.Fields(b))
+	// An exact match must always win over a fuzzy one.
+	if lev > tokens {
+		return lev * 0.99
+	}
+	return tokens * 0.99
+}
+
+// tokenSimilarity returns the Dice coefficient of two sets of words.
+func tokenSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, w := range a {
+		set[w] = true
+	}
+	common := 0
+	for _, w := range b {
+		if set[w] {
+			common++
+			delete(set, w)
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+func clampScore(score float64) float64 {
+	if score < 0 {
+		return 0
+	}
+	if score > 1 {
+		return 1
+	}
+	return score
+}
@@ -0,0 +1,57 @@
+package tvdb_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/pioz/tvdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankSearchResults(t *testing.T) {
+	results := []tvdb.Series{
+		{ID: 1, SeriesName: "Game of Thrones: Cartoon Parody"},
+		{ID: 2, SeriesName: "Game of Thrones", FirstAired: "2011-04-17", Network: "HBO"},
+		{ID: 3, SeriesName: "Thrones", Aliases: []string{"Game of Thrones"}},
+	}
+	matches := tvdb.RankSearchResults("game of thrones", results, tvdb.SearchHints{})
+	assert.Equal(t, 2, matches[0].Series.ID)
+	assert.Equal(t, 1.0, matches[0].Score)
+	assert.Equal(t, 3, matches[1].Series.ID)
+
+	matches = tvdb.RankSearchResults("Game of Throne", results, tvdb.SearchHints{})
+	assert.Equal(t, 2, matches[0].Series.ID)
+	assert.Less(t, matches[0].Score, 1.0)
+	assert.Greater(t, matches[0].Score, 0.9)
+
+	results = []tvdb.Series{
+		{ID: 1, SeriesName: "Doctor Who", FirstAired: "1963-11-23"},
+		{ID: 2, SeriesName: "Doctor Who (2005)", FirstAired: "2005-03-26"},
+	}
+	matches = tvdb.RankSearchResults("Doctor Who (2005)", results, tvdb.SearchHints{})
+	assert.Equal(t, 2, matches[0].Series.ID)
+
+	results = []tvdb.Series{
+		{ID: 1, SeriesName: "The Office (UK)"},
+		{ID: 2, SeriesName: "The Office (US)"},
+	}
+	matches = tvdb.RankSearchResults("office", results, tvdb.SearchHints{Country: "us"})
+	assert.Equal(t, 2, matches[0].Series.ID)
+
+	results = []tvdb.Series{{ID: 1, SeriesName: "Pokémon"}, {ID: 2, SeriesName: "Poker"}}
+	matches = tvdb.RankSearchResults("pokemon", results, tvdb.SearchHints{})
+	assert.Equal(t, 1, matches[0].Series.ID)
+	assert.Equal(t, 1.0, matches[0].Score)
+
+	assert.Empty(t, tvdb.RankSearchResults("pokemon", nil, tvdb.SearchHints{}))
+}
+
+func TestBestSearchNoResults(t *testing.T) {
+	cache := tvdb.NewDiskCache(t.TempDir(), true)
+	if err := cache.Seed(tvdb.CacheKey("/search/series", url.Values{"name": {"nothing"}}, "en"), []byte(`{"data":[]}`)); err != nil {
+		t.Fatal(err)
+	}
+	c := tvdb.Client{Language: "en", Cache: cache}
+	_, err := c.BestSearch("nothing")
+	assert.Equal(t, tvdb.ErrNoResults, err)
+}
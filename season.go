@@ -0,0 +1,108 @@
+package tvdb
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Season struct store all data of a season of a series. Seasons are not
+// returned by the TVDB api, they are built from the series episodes, summary
+// and images using the Series methods Season and AllSeasons.
+type Season struct {
+	Number int
+	// Episodes of the season sorted by aired episode number.
+	Episodes []*Episode
+	// First and last aired date of the season episodes (format 2006-01-02).
+	FirstAired string
+	LastAired  string
+	// Season poster images, filled only if the series images have been
+	// retrieved with GetSeriesSeasonImages.
+	Images []Image
+	// Listed is true if the season is listed in series.Summary.AiredSeasons.
+	// The summary has no per season episode count, so EpisodeCount can be
+	// compared only with the total of all the seasons, see
+	// Series.EpisodeCounts.
+	Listed bool
+}
+
+// EpisodeCount returns the number of episodes of the season.
+func (s *Season) EpisodeCount() int {
+	return len(s.Episodes)
+}
+
+// EpisodeCounts returns the number of episodes of the series held in
+// series.Episodes and the number of aired episodes of all the seasons
+// reported by series.Summary.AiredEpisodes. If the summary has not been
+// retrieved or its count is not a number ok is false.
+func (s *Series) EpisodeCounts() (held, aired int, ok bool) {
+	aired, err := strconv.Atoi(s.Summary.AiredEpisodes)
+	if err != nil {
+		return len(s.Episodes), 0, false
+	}
+	return len(s.Episodes), aired, true
+}
+
+// Season returns the season of the series by number. Returns nil if the
+// season has no episodes and is not listed in the series summary.
+func (s *Series) Season(number int) *Season {
+	season := s.buildSeason(number)
+	if season.EpisodeCount() == 0 && !season.Listed {
+		return nil
+	}
+	return season
+}
+
+// AllSeasons returns all the seasons of the series sorted by number. Seasons
+// are collected from series.Episodes and series.Summary.AiredSeasons, so a
+// season listed in the summary but without retrieved episodes is returned with
+// an empty episodes slice.
+func (s *Series) AllSeasons() []Season {
+	numbers := make(map[int]bool)
+	for _, ep := range s.Episodes {
+		numbers[ep.AiredSeason] = true
+	}
+	for _, n := range s.Summary.AiredSeasons {
+		if number, err := strconv.Atoi(n); err == nil {
+			numbers[number] = true
+		}
+	}
+	seasons := make([]Season, 0, len(numbers))
+	for number := range numbers {
+		seasons = append(seasons, *s.buildSeason(number))
+	}
+	sort.Slice(seasons, func(i, j int) bool {
+		return seasons[i].Number < seasons[j].Number
+	})
+	return seasons
+}
+
+func (s *Series) buildSeason(number int) *Season {
+	season := &Season{Number: number, Episodes: s.GetSeasonEpisodes(number)}
+	sort.SliceStable(season.Episodes, func(i, j int) bool {
+		return season.Episodes[i].AiredEpisodeNumber < season.Episodes[j].AiredEpisodeNumber
+	})
+	for _, ep := range season.Episodes {
+		if ep.FirstAired == "" {
+			continue
+		}
+		if season.FirstAired == "" || ep.FirstAired < season.FirstAired {
+			season.FirstAired = ep.FirstAired
+		}
+		if ep.FirstAired > season.LastAired {
+			season.LastAired = ep.FirstAired
+		}
+	}
+	subKey := strconv.Itoa(number)
+	for _, image := range s.Images {
+		if image.KeyType == "season" && image.SubKey == subKey {
+			season.Images = append(season.Images, image)
+		}
+	}
+	for _, n := range s.Summary.AiredSeasons {
+		if n == subKey {
+			season.Listed = true
+			break
+		}
+	}
+	return season
+}
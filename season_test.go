@@ -0,0 +1,65 @@
+package tvdb_test
+
+import (
+	"testing"
+
+	"github.com/pioz/tvdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeriesSeasons(t *testing.T) {
+	s := tvdb.Series{
+		ID: 1,
+		Episodes: []tvdb.Episode{
+			{ID: 2, AiredSeason: 1, AiredEpisodeNumber: 2, FirstAired: "2000-01-08"},
+			{ID: 1, AiredSeason: 1, AiredEpisodeNumber: 1, FirstAired: "2000-01-01"},
+			{ID: 3, AiredSeason: 2, AiredEpisodeNumber: 1},
+		},
+		Summary: tvdb.Summary{AiredSeasons: []string{"1", "2", "3"}},
+		Images: []tvdb.Image{
+			{ID: 1, KeyType: "season", SubKey: "1"},
+			{ID: 2, KeyType: "season", SubKey: "2"},
+			{ID: 3, KeyType: "poster"},
+		},
+	}
+	season := s.Season(1)
+	assert.Equal(t, 2, season.EpisodeCount())
+	assert.Equal(t, 1, season.Episodes[0].ID)
+	assert.Equal(t, "2000-01-01", season.FirstAired)
+	assert.Equal(t, "2000-01-08", season.LastAired)
+	assert.Equal(t, 1, len(season.Images))
+	assert.True(t, season.Listed)
+	assert.Nil(t, s.Season(4))
+
+	seasons := s.AllSeasons()
+	assert.Equal(t, 3, len(seasons))
+	assert.Equal(t, 0, seasons[2].EpisodeCount())
+	assert.True(t, seasons[2].Listed)
+	assert.Equal(t, "", seasons[1].FirstAired)
+}
+
+func TestSeriesEpisodeCounts(t *testing.T) {
+	s := tvdb.Series{
+		ID: 1,
+		Episodes: []tvdb.Episode{
+			{ID: 1, AiredSeason: 1, AiredEpisodeNumber: 1},
+			{ID: 2, AiredSeason: 1, AiredEpisodeNumber: 2},
+			{ID: 3, AiredSeason: 2, AiredEpisodeNumber: 1},
+		},
+	}
+	held, _, ok := s.EpisodeCounts()
+	assert.Equal(t, 3, held)
+	assert.False(t, ok, "the summary has not been retrieved")
+
+	s.Summary = tvdb.Summary{AiredEpisodes: "4", AiredSeasons: []string{"1", "2"}}
+	held, aired, ok := s.EpisodeCounts()
+	assert.True(t, ok)
+	assert.Equal(t, 3, held)
+	assert.Equal(t, 4, aired)
+	// The seasons are listed but the missing episode can't be attributed to
+	// one of them.
+	for _, season := range s.AllSeasons() {
+		assert.True(t, season.Listed)
+	}
+	assert.Equal(t, 2, s.Season(1).EpisodeCount())
+}
@@ -1,5 +1,11 @@
 package tvdb
 
+import (
+	"math"
+	"sort"
+	"time"
+)
+
 // Series struct store all data of an episode.
 type Series struct {
 	Added           string   `json:"added"`
@@ -32,6 +38,8 @@ type Series struct {
 	Summary Summary
 	// Slice of the series images.
 	Images []Image
+	// Lookup index of the series episodes, built lazily.
+	index *episodeIndex
 }
 
 // Empty verify if the series's fields are empty and don't are filled by an api
@@ -48,22 +56,162 @@ func (s *Series) BannerURL() string {
 // GetSeasonEpisodes select and returns the episodes of the series by season
 // number.
 func (s *Series) GetSeasonEpisodes(season int) []*Episode {
-	episodes := make([]*Episode, 0)
-	for i := range s.Episodes {
-		if s.Episodes[i].AiredSeason == season {
-			episodes = append(episodes, &s.Episodes[i])
-		}
-	}
-	return episodes
+	return s.lookupEpisodes(func(idx *episodeIndex) []int {
+		return idx.bySeason[season]
+	}, func(ep *Episode) bool {
+		return ep.AiredSeason == season
+	})
 }
 
 // GetEpisode select and returns a specific episode of the series by season and
 // episode number.
 func (s *Series) GetEpisode(season, number int) *Episode {
+	return s.lookupEpisode(func(idx *episodeIndex) (int, bool) {
+		i, ok := idx.byAired[[2]int{season, number}]
+		return i, ok
+	}, func(ep *Episode) bool {
+		return ep.AiredSeason == season && ep.AiredEpisodeNumber == number
+	})
+}
+
+// GetEpisodeByID select and returns a specific episode of the series by its
+// TVDB id.
+func (s *Series) GetEpisodeByID(id int) *Episode {
+	return s.lookupEpisode(func(idx *episodeIndex) (int, bool) {
+		i, ok := idx.byID[id]
+		return i, ok
+	}, func(ep *Episode) bool {
+		return ep.ID == id
+	})
+}
+
+// GetDvdEpisode select and returns a specific episode of the series by DVD
+// season and DVD episode number.
+func (s *Series) GetDvdEpisode(season int, number float64) *Episode {
+	return s.lookupEpisode(func(idx *episodeIndex) (int, bool) {
+		i, ok := idx.byDvd[dvdNumber{season, number}]
+		return i, ok
+	}, func(ep *Episode) bool {
+		return ep.DvdSeason == season && ep.DvdEpisodeNumber == number
+	})
+}
+
+// GetAbsoluteEpisode select and returns a specific episode of the series by
+// absolute number.
+func (s *Series) GetAbsoluteEpisode(number int) *Episode {
+	return s.lookupEpisode(func(idx *episodeIndex) (int, bool) {
+		i, ok := idx.byAbs[number]
+		return i, ok
+	}, func(ep *Episode) bool {
+		return ep.AbsoluteNumber == number
+	})
+}
+
+// GetEpisodeByImdbID select and returns a specific episode of the series by
+// IMDB id.
+func (s *Series) GetEpisodeByImdbID(imdbID string) *Episode {
+	return s.lookupEpisode(func(idx *episodeIndex) (int, bool) {
+		i, ok := idx.byImdb[imdbID]
+		return i, ok
+	}, func(ep *Episode) bool {
+		return ep.ImdbID == imdbID
+	})
+}
+
+// EpisodesOn select and returns the episodes of the series first aired on the
+// date, sorted by aired season and episode number. Only the year, month and
+// day of date are used.
+func (s *Series) EpisodesOn(date time.Time) []*Episode {
+	day := date.Format("2006-01-02")
+	episodes := s.lookupEpisodes(func(idx *episodeIndex) []int {
+		return idx.byDate[day]
+	}, func(ep *Episode) bool {
+		return ep.FirstAired == day
+	})
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return airedLess(episodes[i], episodes[j])
+	})
+	return episodes
+}
+
+// ChronologicalEpisodes returns all the episodes of the series in broadcast
+// order. Regular episodes are sorted by aired season and episode number, while
+// specials (season 0) are placed using their airsBeforeSeason,
+// airsBeforeEpisode and airsAfterSeason fields. Specials without placement
+// fields are positioned by their first aired date, or at the end if the date
+// is unknown.
+func (s *Series) ChronologicalEpisodes() []*Episode {
+	regulars := make([]*Episode, 0, len(s.Episodes))
+	specials := make([]*Episode, 0)
 	for i := range s.Episodes {
-		if s.Episodes[i].AiredSeason == season && s.Episodes[i].AiredEpisodeNumber == number {
-			return &s.Episodes[i]
+		if s.Episodes[i].AiredSeason == 0 {
+			specials = append(specials, &s.Episodes[i])
+		} else {
+			regulars = append(regulars, &s.Episodes[i])
+		}
+	}
+	sort.SliceStable(regulars, func(i, j int) bool {
+		return airedLess(regulars[i], regulars[j])
+	})
+
+	keys := make(map[*Episode]chronoKey, len(s.Episodes))
+	for _, ep := range regulars {
+		keys[ep] = chronoKey{ep.AiredSeason, ep.AiredEpisodeNumber, 1, 0}
+	}
+	for _, ep := range specials {
+		keys[ep] = specialKey(ep, regulars)
+	}
+
+	episodes := append(regulars, specials...)
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return keys[episodes[i]].less(keys[episodes[j]])
+	})
+	return episodes
+}
+
+// chronoKey is the sort key used to interleave specials with regular episodes.
+// Specials placed before an episode get slot 0, regular episodes slot 1 and
+// specials placed after a season slot 2.
+type chronoKey struct {
+	season, episode, slot, special int
+}
+
+func (k chronoKey) less(o chronoKey) bool {
+	if k.season != o.season {
+		return k.season < o.season
+	}
+	if k.episode != o.episode {
+		return k.episode < o.episode
+	}
+	if k.slot != o.slot {
+		return k.slot < o.slot
+	}
+	return k.special < o.special
+}
+
+func specialKey(ep *Episode, regulars []*Episode) chronoKey {
+	n := ep.AiredEpisodeNumber
+	switch {
+	case ep.AirsBeforeSeason > 0 && ep.AirsBeforeEpisode > 0:
+		return chronoKey{ep.AirsBeforeSeason, ep.AirsBeforeEpisode, 0, n}
+	case ep.AirsBeforeSeason > 0:
+		return chronoKey{ep.AirsBeforeSeason, math.MinInt32, 0, n}
+	case ep.AirsAfterSeason > 0:
+		return chronoKey{ep.AirsAfterSeason, math.MaxInt32, 2, n}
+	}
+	if aired, ok := parseAirDate(ep.FirstAired); ok {
+		for _, r := range regulars {
+			if d, ok := parseAirDate(r.FirstAired); ok && d.After(aired) {
+				return chronoKey{r.AiredSeason, r.AiredEpisodeNumber, 0, n}
+			}
 		}
 	}
-	return nil
+	return chronoKey{math.MaxInt32, math.MaxInt32, 2, n}
+}
+
+func airedLess(a, b *Episode) bool {
+	if a.AiredSeason != b.AiredSeason {
+		return a.AiredSeason < b.AiredSeason
+	}
+	return a.AiredEpisodeNumber < b.AiredEpisodeNumber
 }
@@ -0,0 +1,107 @@
+package tvdb_test
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pioz/tvdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeriesChronologicalEpisodes(t *testing.T) {
+	s := tvdb.Series{ID: 1, Episodes: []tvdb.Episode{
+		{ID: 4, AiredSeason: 2, AiredEpisodeNumber: 1, FirstAired: "2001-01-10"},
+		{ID: 2, AiredSeason: 1, AiredEpisodeNumber: 2, FirstAired: "2000-01-08"},
+		{ID: 1, AiredSeason: 1, AiredEpisodeNumber: 1, FirstAired: "2000-01-01"},
+		{ID: 5, AiredSeason: 2, AiredEpisodeNumber: 2, FirstAired: "2001-01-17"},
+		{ID: 10, AiredSeason: 0, AiredEpisodeNumber: 1, AirsAfterSeason: 1},
+		{ID: 11, AiredSeason: 0, AiredEpisodeNumber: 2, AirsBeforeSeason: 2, AirsBeforeEpisode: 2},
+		{ID: 12, AiredSeason: 0, AiredEpisodeNumber: 3, FirstAired: "2000-01-05"},
+		{ID: 13, AiredSeason: 0, AiredEpisodeNumber: 4},
+		{ID: 14, AiredSeason: 0, AiredEpisodeNumber: 5, AirsBeforeSeason: 1},
+	}}
+	ids := make([]int, 0)
+	for _, ep := range s.ChronologicalEpisodes() {
+		ids = append(ids, ep.ID)
+	}
+	assert.Equal(t, []int{14, 1, 12, 2, 10, 4, 11, 5, 13}, ids)
+}
+
+func TestSeriesEpisodeLookups(t *testing.T) {
+	s := tvdb.Series{ID: 1, Episodes: []tvdb.Episode{
+		{ID: 1, AiredSeason: 1, AiredEpisodeNumber: 1, DvdSeason: 1, DvdEpisodeNumber: 1.1, AbsoluteNumber: 1, ImdbID: "tt1"},
+		{ID: 2, AiredSeason: 1, AiredEpisodeNumber: 2, DvdSeason: 1, DvdEpisodeNumber: 1.2, AbsoluteNumber: 2},
+	}}
+	assert.Equal(t, 2, s.GetEpisode(1, 2).ID)
+	assert.Equal(t, 2, s.GetEpisodeByID(2).AiredEpisodeNumber)
+	assert.Equal(t, 2, s.GetDvdEpisode(1, 1.2).ID)
+	assert.Equal(t, 1, s.GetAbsoluteEpisode(1).ID)
+	assert.Equal(t, 1, s.GetEpisodeByImdbID("tt1").ID)
+	assert.Equal(t, 2, len(s.GetSeasonEpisodes(1)))
+	assert.Nil(t, s.GetEpisode(2, 1))
+
+	// Replacing the episodes invalidates the index
+	s.Episodes = []tvdb.Episode{{ID: 3, AiredSeason: 2, AiredEpisodeNumber: 1}}
+	assert.Nil(t, s.GetEpisode(1, 2))
+	assert.Equal(t, 3, s.GetEpisode(2, 1).ID)
+	s.Episodes = append(s.Episodes, tvdb.Episode{ID: 4, AiredSeason: 2, AiredEpisodeNumber: 2})
+	assert.Equal(t, 4, s.GetEpisode(2, 2).ID)
+}
+
+func TestSeriesEpisodeLookupsInPlaceChanges(t *testing.T) {
+	s := tvdb.Series{ID: 1, Episodes: []tvdb.Episode{
+		{ID: 1, AiredSeason: 1, AiredEpisodeNumber: 1},
+		{ID: 2, AiredSeason: 1, AiredEpisodeNumber: 2},
+	}}
+	assert.Equal(t, 1, s.GetEpisode(1, 1).ID)
+
+	// Sorting in place keeps the same slice
+	sort.Slice(s.Episodes, func(i, j int) bool { return s.Episodes[i].ID > s.Episodes[j].ID })
+	assert.Equal(t, 1, s.GetEpisode(1, 1).ID)
+	assert.Equal(t, 2, s.GetEpisodeByID(2).ID)
+
+	// Editing a field in place
+	s.Episodes[0].AiredEpisodeNumber = 3
+	assert.Nil(t, s.GetEpisode(1, 2))
+	assert.Equal(t, 2, s.GetEpisode(1, 3).ID)
+	assert.Equal(t, 1, s.GetEpisode(1, 1).ID)
+
+	// Moving an episode to another season in place
+	assert.Len(t, s.GetSeasonEpisodes(1), 2)
+	s.Episodes[0].AiredSeason = 2
+	if assert.Len(t, s.GetSeasonEpisodes(1), 1) {
+		assert.Equal(t, 1, s.GetSeasonEpisodes(1)[0].ID)
+	}
+	assert.Len(t, s.GetSeasonEpisodes(2), 1)
+}
+
+func TestSeriesEpisodeLookupsConcurrent(t *testing.T) {
+	s := tvdb.Series{ID: 1, Episodes: []tvdb.Episode{
+		{ID: 1, AiredSeason: 1, AiredEpisodeNumber: 1},
+		{ID: 2, AiredSeason: 1, AiredEpisodeNumber: 2},
+	}}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, 1, s.GetEpisode(1, 1).ID)
+			assert.Len(t, s.GetSeasonEpisodes(1), 2)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSeriesEpisodesOn(t *testing.T) {
+	s := tvdb.Series{ID: 1, Episodes: []tvdb.Episode{
+		{ID: 2, AiredSeason: 1, AiredEpisodeNumber: 2, FirstAired: "2024-03-05"},
+		{ID: 1, AiredSeason: 1, AiredEpisodeNumber: 1, FirstAired: "2024-03-05"},
+		{ID: 3, AiredSeason: 1, AiredEpisodeNumber: 3, FirstAired: "2024-03-06"},
+	}}
+	episodes := s.EpisodesOn(time.Date(2024, 3, 5, 22, 30, 0, 0, time.UTC))
+	assert.Equal(t, 2, len(episodes))
+	assert.Equal(t, 1, episodes[0].ID)
+	assert.Empty(t, s.EpisodesOn(time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,47 @@
+package tvdb
+
+import "sync"
+
+// inflight coalesces the identical GET requests performed concurrently by
+// all the clients. It is global because clients are often copied by value.
+var inflight flightGroup
+
+// flightGroup runs only one call at a time for each key: callers arriving
+// while a call is in flight wait for it and share its result.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	wg   sync.WaitGroup
+	data []byte
+	err  error
+}
+
+// do executes fn, or waits for the in-flight execution with the same key, and
+// returns its result. The returned data is shared and must not be modified.
+func (g *flightGroup) do(key string, fn func() ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.data, call.err
+	}
+	call := new(flightCall)
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		call.wg.Done()
+	}()
+	call.data, call.err = fn()
+	return call.data, call.err
+}
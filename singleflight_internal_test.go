@@ -0,0 +1,44 @@
+package tvdb
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlightGroup(t *testing.T) {
+	var g flightGroup
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+	var joined sync.Once
+	results := make([][]byte, 10)
+	ready := make(chan struct{}, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ready <- struct{}{}
+			results[i], _ = g.do("key", func() ([]byte, error) {
+				calls.Add(1)
+				// Return only when all the callers are about to join the call.
+				joined.Do(func() {
+					for range results {
+						<-ready
+					}
+					time.Sleep(50 * time.Millisecond)
+				})
+				return []byte("data"), nil
+			})
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+	for _, r := range results {
+		assert.Equal(t, []byte("data"), r)
+	}
+	data, _ := g.do("key", func() ([]byte, error) { return []byte("again"), nil })
+	assert.Equal(t, []byte("again"), data)
+}
@@ -0,0 +1,89 @@
+package tvdb
+
+import "errors"
+
+// ErrNotStored is returned by a Store when the requested record is not
+// stored.
+var ErrNotStored = errors.New("record not stored")
+
+// Store persists a local copy of the TVDB records. The episodes, actors and
+// images of a series are stored apart from the series record, by series id;
+// PutSeries ignores the Episodes, Actors and Images fields of the series. Get
+// methods return ErrNotStored if the record is not stored and Delete methods
+// don't fail if it is not stored. Implementations must be safe for concurrent
+// use.
+type Store interface {
+	GetSeries(id int) (Series, error)
+	PutSeries(s Series) error
+	DeleteSeries(id int) error
+
+	GetEpisodes(seriesID int) ([]Episode, error)
+	PutEpisodes(seriesID int, episodes []Episode) error
+	DeleteEpisodes(seriesID int) error
+
+	GetActors(seriesID int) ([]Actor, error)
+	PutActors(seriesID int, actors []Actor) error
+	DeleteActors(seriesID int) error
+
+	GetImages(seriesID int) ([]Image, error)
+	PutImages(seriesID int, images []Image) error
+	DeleteImages(seriesID int) error
+
+	GetCheckpoint(name string) (Checkpoint, error)
+	PutCheckpoint(name string, c Checkpoint) error
+	DeleteCheckpoint(name string) error
+}
+
+// Checkpoint is the state of a sync of a Store with the TVDB api, saved by
+// name so that more syncs can share the same Store.
+type Checkpoint struct {
+	// Epoch time of the last sync.
+	Time int64 `json:"time"`
+	// Series loaded at least once.
+	Series []int `json:"series"`
+	// Series that failed to sync and will be retried at the next sync.
+	Pending []int `json:"pending"`
+	// Series not found on TVDB and removed from the Store. They are retrieved
+	// again only if they appear in the updates.
+	Removed []int `json:"removed,omitempty"`
+}
+
+// LoadSeries returns the series identified by id stored in st with its
+// episodes, actors and images. Returns ErrNotStored if the series record is
+// not stored, missing episodes, actors or images are left empty.
+func LoadSeries(st Store, id int) (Series, error) {
+	s, err := st.GetSeries(id)
+	if err != nil {
+		return s, err
+	}
+	if s.Episodes, err = st.GetEpisodes(id); err != nil && !errors.Is(err, ErrNotStored) {
+		return s, err
+	}
+	if s.Actors, err = st.GetActors(id); err != nil && !errors.Is(err, ErrNotStored) {
+		return s, err
+	}
+	if s.Images, err = st.GetImages(id); err != nil && !errors.Is(err, ErrNotStored) {
+		return s, err
+	}
+	return s, nil
+}
+
+// SaveSeries stores in st the series with its episodes, actors and images.
+func SaveSeries(st Store, s Series) error {
+	if err := st.PutSeries(s); err != nil {
+		return err
+	}
+	if err := st.PutEpisodes(s.ID, s.Episodes); err != nil {
+		return err
+	}
+	if err := st.PutActors(s.ID, s.Actors); err != nil {
+		return err
+	}
+	return st.PutImages(s.ID, s.Images)
+}
+
+// DeleteSeries removes from st the series identified by id with its episodes,
+// actors and images.
+func DeleteSeries(st Store, id int) error {
+	return errors.Join(st.DeleteSeries(id), st.DeleteEpisodes(id), st.DeleteActors(id), st.DeleteImages(id))
+}
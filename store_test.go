@@ -0,0 +1,63 @@
+package tvdb_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pioz/tvdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := tvdb.OpenDirStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.FileExists(t, filepath.Join(dir, "version"))
+
+	_, err = tvdb.LoadSeries(store, 121361)
+	assert.Equal(t, tvdb.ErrNotStored, err)
+
+	s := tvdb.Series{
+		ID:         121361,
+		SeriesName: "Game of Thrones",
+		Genre:      []string{"Drama", "Fantasy"},
+		Episodes:   []tvdb.Episode{{ID: 3254641, AiredSeason: 1, AiredEpisodeNumber: 1, EpisodeName: "Winter Is Coming"}},
+		Actors:     []tvdb.Actor{{ID: 7, Name: "Peter Dinklage"}},
+		Images:     []tvdb.Image{{ID: 9, KeyType: "poster", RatingsInfo: tvdb.Rating{Average: 8.5, Count: 3}}},
+	}
+	assert.Nil(t, tvdb.SaveSeries(store, s))
+	loaded, err := tvdb.LoadSeries(store, s.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, s, loaded)
+	assert.NotNil(t, loaded.GetEpisode(1, 1))
+
+	record, err := store.GetSeries(s.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, record.Episodes, "episodes are not stored with the series record")
+
+	checkpoint := tvdb.Checkpoint{Time: 1500000000, Series: []int{121361}, Pending: []int{}}
+	assert.Nil(t, store.PutCheckpoint("my sync", checkpoint))
+	c, err := store.GetCheckpoint("my sync")
+	assert.Nil(t, err)
+	assert.Equal(t, checkpoint, c)
+	assert.Nil(t, store.DeleteCheckpoint("my sync"))
+	_, err = store.GetCheckpoint("my sync")
+	assert.Equal(t, tvdb.ErrNotStored, err)
+
+	assert.Nil(t, tvdb.DeleteSeries(store, s.ID))
+	assert.Nil(t, tvdb.DeleteSeries(store, s.ID), "deleting a missing series doesn't fail")
+	_, err = store.GetEpisodes(s.ID)
+	assert.Equal(t, tvdb.ErrNotStored, err)
+}
+
+func TestDirStoreVersion(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "version"), []byte("999\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := tvdb.OpenDirStore(dir)
+	assert.ErrorIs(t, err, tvdb.ErrStoreVersion)
+}
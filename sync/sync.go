@@ -0,0 +1,313 @@
+// Package sync keeps a local mirror of a set of TVDB series.
+//
+// The first sync loads every series with its episodes, actors and images and
+// saves them in a tvdb.Store. The next syncs ask the TVDB updates since the
+// last sync and retrieve again only the followed series that changed,
+// reporting what changed for each of them. The time of the last sync is
+// persisted in the Store as a tvdb.Checkpoint together with the series that
+// failed to sync, which are retried at the next sync, and the series deleted
+// from TVDB, which are removed from the Store.
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pioz/tvdb"
+)
+
+// maxUpdatesAge is the oldest checkpoint that can be synced with the updates:
+// the api returns the updates of at most one week, so older mirrors are
+// loaded again.
+const maxUpdatesAge = 7 * 24 * time.Hour
+
+// Change describes what changed in a series since the last sync.
+type Change struct {
+	// True if the series has been loaded for the first time. The diff of a new
+	// series is empty.
+	New bool `json:"new"`
+	tvdb.SeriesDiff
+}
+
+// Empty returns true if nothing changed.
+func (c *Change) Empty() bool {
+	return !c.New && c.SeriesDiff.Empty()
+}
+
+// Report is the result of a sync.
+type Report struct {
+	// True if all the followed series have been loaded, because it is the first
+	// sync or the last one is too old to use the updates.
+	Full bool
+	// Series retrieved during the sync.
+	Synced []int
+	// Series that failed to sync.
+	Failed []int
+	// Series not found on TVDB, removed from the store.
+	Removed []int
+	// Changes of the synced series, sorted by series id. Series that didn't
+	// change are omitted.
+	Changes []Change
+	// Checkpoint saved at the end of the sync.
+	Checkpoint tvdb.Checkpoint
+}
+
+// Syncer syncs a tvdb.Store with the TVDB api.
+type Syncer struct {
+	Client *tvdb.Client
+	Store  tvdb.Store
+	// Name of the checkpoint of the sync in the store (default "sync"). Syncs
+	// of different sets of series sharing a store need different names.
+	Name string
+	// Number of series retrieved concurrently (default 8).
+	Workers int
+	// Number of times a request or a series that failed with a temporary
+	// error is retried (default 3).
+	Retries int
+	// Delay before the first retry, doubled at every retry (default 1
+	// second).
+	RetryDelay time.Duration
+}
+
+// New returns a Syncer that mirrors the series retrieved with the client c in
+// the store st.
+func New(c *tvdb.Client, st tvdb.Store) *Syncer {
+	return &Syncer{Client: c, Store: st, Name: "sync", Workers: 8, Retries: 3, RetryDelay: time.Second}
+}
+
+// Sync brings the mirror of the followed series identified by ids up to date.
+// New series, series updated since the last sync and series that failed the
+// last time are retrieved and saved, then the checkpoint is saved. Series not
+// found are removed from the store and not retried. Series that can't be
+// synced are skipped and their errors are joined in the returned error. If the checkpoint can't be read or saved, or the updates can't be
+// retrieved, the sync fails.
+func (s *Syncer) Sync(ctx context.Context, ids []int) (Report, error) {
+	var report Report
+	checkpoint, err := s.Store.GetCheckpoint(s.name())
+	if err != nil && !errors.Is(err, tvdb.ErrNotStored) {
+		return report, err
+	}
+	start := time.Now()
+
+	followed := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		followed[id] = true
+	}
+	loaded := make(map[int]bool, len(checkpoint.Series))
+	for _, id := range checkpoint.Series {
+		if followed[id] {
+			loaded[id] = true
+		}
+	}
+	removed := make(map[int]bool, len(checkpoint.Removed))
+	for _, id := range checkpoint.Removed {
+		if followed[id] {
+			removed[id] = true
+		}
+	}
+
+	var todo []int
+	if checkpoint.Time == 0 || start.Sub(time.Unix(checkpoint.Time, 0)) > maxUpdatesAge {
+		report.Full = true
+		todo = uniqueIDs(ids)
+	} else {
+		var updates []tvdb.Update
+		err := s.retry(ctx, func() (err error) {
+			updates, err = s.Client.GetUpdates(int(checkpoint.Time))
+			return err
+		})
+		if err != nil && !tvdb.HaveCodeError(404, err) {
+			return report, err
+		}
+		for _, id := range ids {
+			if !loaded[id] && !removed[id] {
+				todo = append(todo, id)
+			}
+		}
+		for _, id := range checkpoint.Pending {
+			todo = append(todo, id)
+		}
+		for _, u := range updates {
+			todo = append(todo, u.ID)
+		}
+		todo = uniqueIDs(todo)
+		n := 0
+		for _, id := range todo {
+			if followed[id] {
+				todo[n] = id
+				n++
+			}
+		}
+		todo = todo[:n]
+	}
+
+	series, errs := s.fetch(ctx, todo)
+	for i, id := range todo {
+		var change Change
+		if notFound(errs[i]) {
+			errs[i] = tvdb.DeleteSeries(s.Store, id)
+			if errs[i] == nil {
+				report.Removed = append(report.Removed, id)
+				delete(loaded, id)
+				removed[id] = true
+				continue
+			}
+		} else if errs[i] == nil {
+			change, errs[i] = s.syncSeries(series[i])
+		}
+		if errs[i] != nil {
+			errs[i] = fmt.Errorf("series %d: %w", id, errs[i])
+			report.Failed = append(report.Failed, id)
+			continue
+		}
+		report.Synced = append(report.Synced, id)
+		loaded[id] = true
+		delete(removed, id)
+		if !change.Empty() {
+			report.Changes = append(report.Changes, change)
+		}
+	}
+	sort.Slice(report.Changes, func(i, j int) bool {
+		return report.Changes[i].SeriesID < report.Changes[j].SeriesID
+	})
+
+	report.Checkpoint = tvdb.Checkpoint{Time: start.Unix(), Series: make([]int, 0, len(loaded)), Pending: make([]int, 0, len(report.Failed))}
+	for id := range loaded {
+		report.Checkpoint.Series = append(report.Checkpoint.Series, id)
+	}
+	sort.Ints(report.Checkpoint.Series)
+	report.Checkpoint.Pending = append(report.Checkpoint.Pending, report.Failed...)
+	for id := range removed {
+		report.Checkpoint.Removed = append(report.Checkpoint.Removed, id)
+	}
+	sort.Ints(report.Checkpoint.Removed)
+	if err := s.Store.PutCheckpoint(s.name(), report.Checkpoint); err != nil {
+		return report, err
+	}
+	return report, errors.Join(errs...)
+}
+
+func (s *Syncer) name() string {
+	if s.Name == "" {
+		return "sync"
+	}
+	return s.Name
+}
+
+// syncSeries saves the retrieved series and returns what changed since the
+// stored version.
+func (s *Syncer) syncSeries(series tvdb.Series) (Change, error) {
+	old, err := tvdb.LoadSeries(s.Store, series.ID)
+	var change Change
+	switch {
+	case errors.Is(err, tvdb.ErrNotStored):
+		change = Change{New: true, SeriesDiff: tvdb.SeriesDiff{SeriesID: series.ID}}
+	case err != nil:
+		return Change{}, err
+	default:
+		change = Change{SeriesDiff: tvdb.Diff(old, series)}
+	}
+	return change, tvdb.SaveSeries(s.Store, series)
+}
+
+// fetch retrieves with GetSeriesBatch the series identified by ids with their
+// episodes, actors and images, returning a series and an error for every id.
+// Resources not found, like the images of a type that the series doesn't
+// have, are left empty. The series that fail with a temporary error are
+// retrieved again in a new batch, up to s.Retries times.
+func (s *Syncer) fetch(ctx context.Context, ids []int) ([]tvdb.Series, []error) {
+	series := make([]tvdb.Series, len(ids))
+	errs := make([]error, len(ids))
+	opts := tvdb.BatchOptions{Workers: s.Workers, Include: tvdb.Include{Episodes: true, Actors: true, Images: true}}
+	// Positions in ids of the series to retrieve.
+	todo := make([]int, len(ids))
+	for i := range todo {
+		todo[i] = i
+	}
+	delay := s.retryDelay()
+	for attempt := 0; ; attempt++ {
+		batch := make([]int, len(todo))
+		for j, i := range todo {
+			batch[j] = ids[i]
+		}
+		var failed []int
+		for j, r := range s.Client.GetSeriesBatch(ctx, batch, opts) {
+			i := todo[j]
+			series[i], errs[i] = r.Series, r.Err
+			if r.Err != nil && temporary(r.Err) {
+				failed = append(failed, i)
+			}
+		}
+		if len(failed) == 0 || attempt >= s.Retries {
+			return series, errs
+		}
+		select {
+		case <-ctx.Done():
+			for _, i := range failed {
+				errs[i] = ctx.Err()
+			}
+			return series, errs
+		case <-time.After(delay):
+		}
+		todo = failed
+		delay *= 2
+	}
+}
+
+// retry calls fn until it succeeds, it returns an error that is not
+// temporary or the retries are exhausted.
+func (s *Syncer) retry(ctx context.Context, fn func() error) error {
+	delay := s.retryDelay()
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= s.Retries || !temporary(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (s *Syncer) retryDelay() time.Duration {
+	if s.RetryDelay <= 0 {
+		return time.Second
+	}
+	return s.RetryDelay
+}
+
+// temporary returns true if the request that returned err can succeed if
+// performed again.
+func temporary(err error) bool {
+	var reqErr *tvdb.RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.Code == 429 || reqErr.Code >= 500
+	}
+	return !errors.Is(err, tvdb.ErrCacheMiss) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// notFound returns true if err is a 404 response error, the series has been
+// deleted from TVDB.
+func notFound(err error) bool {
+	var reqErr *tvdb.RequestError
+	return errors.As(err, &reqErr) && reqErr.Code == 404
+}
+
+// uniqueIDs returns the ids without duplicates, in order of first occurrence.
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
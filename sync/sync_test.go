@@ -0,0 +1,143 @@
+package sync_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pioz/tvdb"
+	"github.com/pioz/tvdb/sync"
+	"github.com/stretchr/testify/assert"
+)
+
+func seed(t *testing.T, cache *tvdb.DiskCache, path string, params url.Values, data string) {
+	if err := cache.Seed(tvdb.CacheKey(path, params, "en"), []byte(data)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func seedSeries(t *testing.T, cache *tvdb.DiskCache, id int, episodes string) {
+	seed(t, cache, fmt.Sprintf("/series/%d", id), nil, fmt.Sprintf(`{"data":{"id":%d,"seriesName":"Series %d"}}`, id, id))
+	seed(t, cache, fmt.Sprintf("/series/%d/episodes/query", id), url.Values{"page": {"1"}}, `{"data":`+episodes+`}`)
+	seed(t, cache, fmt.Sprintf("/series/%d/actors", id), nil, `{"data":[{"id":1,"name":"Actor"}]}`)
+	for _, keyType := range []string{"fanart", "poster", "season", "seasonwide", "series"} {
+		seed(t, cache, fmt.Sprintf("/series/%d/images/query", id), url.Values{"keyType": {keyType}}, `{"data":[]}`)
+	}
+}
+
+func TestSync(t *testing.T) {
+	cache := tvdb.NewDiskCache(t.TempDir(), true)
+	seedSeries(t, cache, 1, `[{"id":11,"airedSeason":1,"airedEpisodeNumber":1}]`)
+	seedSeries(t, cache, 2, `[{"id":21,"airedSeason":1,"airedEpisodeNumber":1}]`)
+	store, err := tvdb.OpenDirStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncer := sync.New(&tvdb.Client{Language: "en", Cache: cache}, store)
+
+	report, err := syncer.Sync(context.Background(), []int{1, 2, 3})
+	assert.NotNil(t, err, "series 3 is not available")
+	assert.True(t, report.Full)
+	assert.Equal(t, []int{1, 2}, report.Synced)
+	assert.Equal(t, []int{3}, report.Failed)
+	assert.Len(t, report.Changes, 2)
+	assert.True(t, report.Changes[0].New)
+	checkpoint, _ := store.GetCheckpoint("sync")
+	assert.Equal(t, []int{1, 2}, checkpoint.Series)
+	assert.Equal(t, []int{3}, checkpoint.Pending)
+	series, err := tvdb.LoadSeries(store, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "Series 1", series.SeriesName)
+	assert.Len(t, series.Episodes, 1)
+	assert.Len(t, series.Actors, 1)
+
+	seedSeries(t, cache, 1, `[{"id":11,"airedSeason":1,"airedEpisodeNumber":1,"episodeName":"Pilot"},{"id":12,"airedSeason":1,"airedEpisodeNumber":2}]`)
+	seedSeries(t, cache, 3, `[]`)
+	seed(t, cache, "/updated/query", url.Values{"fromTime": {fmt.Sprint(report.Checkpoint.Time)}}, `{"data":[{"id":1},{"id":99}]}`)
+	report, err = syncer.Sync(context.Background(), []int{1, 2, 3})
+	assert.Nil(t, err)
+	assert.False(t, report.Full)
+	assert.ElementsMatch(t, []int{1, 3}, report.Synced, "updated and pending series are synced")
+	if assert.Len(t, report.Changes, 2) {
+		change := report.Changes[0]
+		assert.Equal(t, 1, change.SeriesID)
+		assert.False(t, change.New)
+		assert.Equal(t, 12, change.Episodes.Added[0].ID)
+		assert.True(t, change.Episodes.Changed[0].Renamed())
+		assert.Empty(t, change.Fields)
+		assert.Equal(t, 3, report.Changes[1].SeriesID)
+		assert.True(t, report.Changes[1].New)
+	}
+	checkpoint, _ = store.GetCheckpoint("sync")
+	assert.Equal(t, []int{1, 2, 3}, checkpoint.Series)
+	assert.Empty(t, checkpoint.Pending)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestSyncRemovedSeries(t *testing.T) {
+	// The updates are not cached and the other responses not in the cache are
+	// 404, like the ones of a series deleted from TVDB.
+	var requests atomic.Int32
+	updates := `{"data":[]}`
+	transport := http.DefaultTransport
+	http.DefaultTransport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/updated/query" {
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(updates)), Request: r}, nil
+		}
+		if strings.HasPrefix(r.URL.Path, "/series/5") {
+			requests.Add(1)
+		}
+		return &http.Response{StatusCode: 404, Body: http.NoBody, Request: r}, nil
+	})
+	t.Cleanup(func() { http.DefaultTransport = transport })
+
+	cache := tvdb.NewDiskCache(t.TempDir(), false)
+	seedSeries(t, cache, 1, `[{"id":11,"airedSeason":1,"airedEpisodeNumber":1}]`)
+	store, err := tvdb.OpenDirStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tvdb.SaveSeries(store, tvdb.Series{ID: 5, SeriesName: "Deleted"}); err != nil {
+		t.Fatal(err)
+	}
+	syncer := sync.New(&tvdb.Client{Language: "en", Cache: cache}, store)
+
+	report, err := syncer.Sync(context.Background(), []int{1, 5})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, report.Synced)
+	assert.Empty(t, report.Failed)
+	assert.Equal(t, []int{5}, report.Removed)
+	_, err = tvdb.LoadSeries(store, 5)
+	assert.ErrorIs(t, err, tvdb.ErrNotStored)
+	checkpoint, _ := store.GetCheckpoint("sync")
+	assert.Equal(t, []int{1}, checkpoint.Series)
+	assert.Empty(t, checkpoint.Pending)
+	assert.Equal(t, []int{5}, checkpoint.Removed)
+
+	// The removed series is not retrieved again until it is updated.
+	requests.Store(0)
+	report, err = syncer.Sync(context.Background(), []int{1, 5})
+	assert.NoError(t, err)
+	assert.Empty(t, report.Synced)
+	assert.Empty(t, report.Removed)
+	assert.Equal(t, int32(0), requests.Load())
+	checkpoint, _ = store.GetCheckpoint("sync")
+	assert.Equal(t, []int{5}, checkpoint.Removed)
+
+	seedSeries(t, cache, 5, `[]`)
+	updates = `{"data":[{"id":5}]}`
+	report, err = syncer.Sync(context.Background(), []int{1, 5})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5}, report.Synced)
+	checkpoint, _ = store.GetCheckpoint("sync")
+	assert.Equal(t, []int{1, 5}, checkpoint.Series)
+	assert.Empty(t, checkpoint.Removed)
+}
@@ -0,0 +1,137 @@
+package tvdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// upcomingWorkers is the number of series retrieved concurrently by Upcoming.
+const upcomingWorkers = 8
+
+// UpcomingEpisode is an episode returned by Upcoming with its series and air
+// time.
+type UpcomingEpisode struct {
+	Series  *Series
+	Episode Episode
+	AirTime time.Time
+}
+
+var airsTimeLayouts = []string{"3:04 PM", "3:04PM", "3:04 pm", "3:04pm", "15:04", "3 PM", "3PM"}
+
+// AirTime returns the air time of the episode e of the series, combining the
+// episode first aired date with the series airs time, interpreted in the
+// location loc. If the series airs time is unknown midnight is used. The
+// boolean is false if the episode has no first aired date.
+func (s *Series) AirTime(e *Episode, loc *time.Location) (time.Time, bool) {
+	date, ok := parseAirDate(e.FirstAired)
+	if !ok {
+		return time.Time{}, false
+	}
+	hour, minute, _ := ParseAirsTime(s.AirsTime)
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc), true
+}
+
+// ParseAirsTime parses the airs time of a series, like "9:00 PM" or "21:00",
+// and returns the hour and the minute. The boolean is false if the airs time
+// is empty or in an unknown format.
+func ParseAirsTime(airs string) (int, int, bool) {
+	airs = strings.TrimSpace(airs)
+	for _, layout := range airsTimeLayouts {
+		if t, err := time.Parse(layout, airs); err == nil {
+			return t.Hour(), t.Minute(), true
+		}
+	}
+	return 0, 0, false
+}
+
+// airsAfter returns the air time of the episode e of the series, computed with
+// AirTime in the location of now, and true if the episode airs after now. If
+// the series airs time is unknown only the dates are compared, so an episode
+// airing today airs after now for the whole day. The first boolean is false if
+// the episode has no first aired date.
+func (s *Series) airsAfter(e *Episode, now time.Time) (time.Time, bool, bool) {
+	t, ok := s.AirTime(e, now.Location())
+	if !ok {
+		return t, false, false
+	}
+	if _, _, known := ParseAirsTime(s.AirsTime); !known {
+		year, month, day := now.Date()
+		return t, true, !t.Before(time.Date(year, month, day, 0, 0, 0, 0, now.Location()))
+	}
+	return t, true, t.After(now)
+}
+
+// NextEpisode returns the first episode of the series airing after now, or
+// nil if there is no such episode. Air times are computed with AirTime in the
+// location of now; if the series airs time is unknown an episode airing today
+// is returned for the whole day.
+func (s *Series) NextEpisode(now time.Time) *Episode {
+	var next *Episode
+	var nextTime time.Time
+	for i := range s.Episodes {
+		t, ok, after := s.airsAfter(&s.Episodes[i], now)
+		if ok && after && (next == nil || t.Before(nextTime)) {
+			next, nextTime = &s.Episodes[i], t
+		}
+	}
+	return next
+}
+
+// LastAiredEpisode returns the last episode of the series aired at or before
+// now, or nil if there is no such episode. Air times are computed with AirTime
+// in the location of now; if the series airs time is unknown an episode airing
+// today is not aired until the next day.
+func (s *Series) LastAiredEpisode(now time.Time) *Episode {
+	var last *Episode
+	var lastTime time.Time
+	for i := range s.Episodes {
+		t, ok, after := s.airsAfter(&s.Episodes[i], now)
+		if ok && !after && (last == nil || !t.Before(lastTime)) {
+			last, lastTime = &s.Episodes[i], t
+		}
+	}
+	return last
+}
+
+// Upcoming retrieves concurrently the series identified by seriesIDs with all
+// their episodes and returns the episodes airing from now to now + window,
+// sorted by air time. Episodes of series with an unknown airs time are
+// returned for the whole air day. Series that can't be retrieved are skipped
+// and their errors are joined in the returned error.
+func (c *Client) Upcoming(ctx context.Context, seriesIDs []int, window time.Duration) ([]UpcomingEpisode, error) {
+	now := time.Now()
+	end := now.Add(window)
+	series := make([]*Series, len(seriesIDs))
+	errs := runWorkers(ctx, len(seriesIDs), upcomingWorkers, func(i int) error {
+		s := &Series{ID: seriesIDs[i]}
+		if err := c.GetSeries(s); err != nil {
+			return fmt.Errorf("series %d: %w", seriesIDs[i], err)
+		}
+		if err := c.GetSeriesEpisodes(s, nil); err != nil {
+			return fmt.Errorf("series %d: %w", seriesIDs[i], err)
+		}
+		series[i] = s
+		return nil
+	})
+
+	upcoming := make([]UpcomingEpisode, 0)
+	for _, s := range series {
+		if s == nil {
+			continue
+		}
+		for _, ep := range s.Episodes {
+			t, ok, after := s.airsAfter(&ep, now)
+			if ok && after && t.Before(end) {
+				upcoming = append(upcoming, UpcomingEpisode{Series: s, Episode: ep, AirTime: t})
+			}
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].AirTime.Before(upcoming[j].AirTime)
+	})
+	return upcoming, errors.Join(errs...)
+}
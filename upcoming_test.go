@@ -0,0 +1,77 @@
+package tvdb_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pioz/tvdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeriesNextAndLastAiredEpisode(t *testing.T) {
+	s := tvdb.Series{ID: 1, AirsTime: "9:00 PM", Episodes: []tvdb.Episode{
+		{ID: 1, FirstAired: "2024-03-01"},
+		{ID: 3, FirstAired: "2024-03-15"},
+		{ID: 2, FirstAired: "2024-03-08"},
+		{ID: 4},
+	}}
+	now := time.Date(2024, 3, 8, 20, 0, 0, 0, time.UTC)
+	assert.Equal(t, 2, s.NextEpisode(now).ID)
+	assert.Equal(t, 1, s.LastAiredEpisode(now).ID)
+	now = now.Add(2 * time.Hour)
+	assert.Equal(t, 3, s.NextEpisode(now).ID)
+	assert.Equal(t, 2, s.LastAiredEpisode(now).ID)
+	assert.Nil(t, s.NextEpisode(now.AddDate(1, 0, 0)))
+
+	airTime, ok := s.AirTime(&s.Episodes[0], time.UTC)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 3, 1, 21, 0, 0, 0, time.UTC), airTime)
+	_, ok = s.AirTime(&s.Episodes[3], time.UTC)
+	assert.False(t, ok)
+}
+
+func TestSeriesNextEpisodeUnknownAirsTime(t *testing.T) {
+	s := tvdb.Series{ID: 1, Episodes: []tvdb.Episode{
+		{ID: 1, FirstAired: "2024-03-01"},
+		{ID: 2, FirstAired: "2024-03-08"},
+	}}
+	// The episode airing today is not yet aired for the whole day.
+	now := time.Date(2024, 3, 8, 20, 0, 0, 0, time.UTC)
+	assert.Equal(t, 2, s.NextEpisode(now).ID)
+	assert.Equal(t, 1, s.LastAiredEpisode(now).ID)
+	now = time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+	assert.Nil(t, s.NextEpisode(now))
+	assert.Equal(t, 2, s.LastAiredEpisode(now).ID)
+}
+
+func TestUpcoming(t *testing.T) {
+	day := func(days int) string {
+		return time.Now().AddDate(0, 0, days).Format("2006-01-02")
+	}
+	cache := tvdb.NewDiskCache(t.TempDir(), true)
+	seeds := map[string]string{
+		tvdb.CacheKey("/series/1", nil, "en"): `{"data":{"id":1,"seriesName":"One"}}`,
+		tvdb.CacheKey("/series/2", nil, "en"): `{"data":{"id":2,"seriesName":"Two"}}`,
+		tvdb.CacheKey("/series/1/episodes/query", url.Values{"page": {"1"}}, "en"): fmt.Sprintf(
+			`{"data":[{"id":10,"firstAired":%q},{"id":11,"firstAired":%q},{"id":13,"firstAired":%q},{"id":14}]}`, day(-1), day(0), day(3)),
+		tvdb.CacheKey("/series/2/episodes/query", url.Values{"page": {"1"}}, "en"): fmt.Sprintf(
+			`{"data":[{"id":22,"firstAired":%q},{"id":23,"firstAired":%q}]}`, day(1), day(30)),
+	}
+	for key, data := range seeds {
+		if err := cache.Seed(key, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := tvdb.Client{Language: "en", Cache: cache}
+	upcoming, err := c.Upcoming(context.Background(), []int{1, 2, 3}, 7*24*time.Hour)
+	assert.ErrorIs(t, err, tvdb.ErrCacheMiss, "series 3 is not available")
+	if assert.Len(t, upcoming, 3) {
+		assert.Equal(t, 11, upcoming[0].Episode.ID, "the episode airing today is upcoming")
+		assert.Equal(t, 22, upcoming[1].Episode.ID)
+		assert.Equal(t, "Two", upcoming[1].Series.SeriesName)
+		assert.Equal(t, 13, upcoming[2].Episode.ID)
+	}
+}
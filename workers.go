@@ -0,0 +1,37 @@
+package tvdb
+
+import (
+	"context"
+	"sync"
+)
+
+// runWorkers calls fn for every index from 0 to n-1 using at most workers
+// goroutines and returns the error of every call. Calls not started because
+// the context is done get the context error.
+func runWorkers(ctx context.Context, n, workers int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	if workers <= 0 {
+		workers = 1
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return errs
+}